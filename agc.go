@@ -0,0 +1,50 @@
+package pocketsphinx
+
+/*
+#include <pocketsphinx.h>
+static int agc_get(ps_decoder_t *ps, float *max){
+#ifdef POCKETSPHINX5
+    return -2;
+#else
+    feat_t *feat = ps_get_feat(ps);
+    if (feat == NULL || feat->agc_struct == NULL)
+        return -1;
+    *max = agc_emax_get(feat->agc_struct);
+    return 0;
+#endif
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+)
+
+//AGCMode is an automatic gain control mode, normalizing the energy (c0) of cepstral vectors so audio recorded at different levels looks alike to the acoustic model.
+type AGCMode string
+
+//AGC modes. AGCEmax estimates the maximum energy from previous utterances and is the only one that works with live audio; the others need the whole utterance.
+const (
+	AGCNone  AGCMode = "none"
+	AGCMax   AGCMode = "max"
+	AGCEmax  AGCMode = "emax"
+	AGCNoise AGCMode = "noise"
+)
+
+//AGCEstimate gets the current estimate of the maximum energy (c0) used by AGCEmax automatic gain control, which adapts to the input level over utterances.
+func (p *PocketSphinx) AGCEstimate() (float64, error) {
+	if err := p.acquire(); err != nil {
+		return 0, err
+	}
+	defer p.release()
+	var emax C.float
+	switch C.agc_get(p.ps, &emax) {
+	case 0:
+	case -2:
+		return 0, fmt.Errorf("agc error:%w", ErrUnsupported)
+	default:
+		return 0, errors.New("agc error:no automatic gain control")
+	}
+	return float64(emax), nil
+}
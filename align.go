@@ -0,0 +1,177 @@
+package pocketsphinx
+
+import (
+	"fmt"
+	"strings"
+)
+
+//WordAlignment is a word of a transcript with its start and end time in seconds in the aligned audio. Phones is only set by AlignPhones.
+type WordAlignment struct {
+	Word   string        `json:"word"`
+	Start  float64       `json:"start"`
+	End    float64       `json:"end"`
+	Phones []PhoneResult `json:"phones,omitempty"`
+}
+
+//alignSearch is the name of the search used for alignment.
+const alignSearch = "_align"
+
+//phoneWordPrefix starts the words added to the dictionary by AlignPhones, one per phone.
+const phoneWordPrefix = "_ph_"
+
+//Align finds the times of the words of transcript in audio, a whole utterance of single channel, 16-bit pcm audio, by decoding it with a grammar only accepting the transcript, with optional silence and fillers between words, or with pocketsphinx 5, with its alignment search. Every word must be in the dictionary. The active search is restored afterwards, and the search used for alignment removed.
+func (p *PocketSphinx) Align(transcript string, audio []int16) ([]WordAlignment, error) {
+	words := strings.Fields(transcript)
+	if err := p.checkTranscript(words); err != nil {
+		return nil, err
+	}
+	if !legacyAPI {
+		ret, err := p.alignNative(words, audio)
+		for i := range ret {
+			ret[i].Phones = nil
+		}
+		return ret, err
+	}
+	segs, err := p.alignSegments(words, audio)
+	if err != nil {
+		return nil, err
+	}
+	if len(segs) != len(words) {
+		return nil, fmt.Errorf("align error:%w", ErrNoHypothesis)
+	}
+	frate := p.frameRate()
+	ret := make([]WordAlignment, len(words))
+	for i, seg := range segs {
+		ret[i] = WordAlignment{
+			Word:  words[i],
+			Start: float64(seg.StartFrame) / frate,
+			End:   float64(seg.EndFrame+1) / frate,
+		}
+	}
+	return ret, nil
+}
+
+//AlignPhones is like Align, but also finds the times of the phones of every word. With the legacy API, it uses the main pronunciation of every word, aligning a grammar of phones rather than words, and adds a word for each phone of the acoustic model to the dictionary the first time it is used. With pocketsphinx 5, the phones are those of the pronunciations chosen by its alignment search.
+func (p *PocketSphinx) AlignPhones(transcript string, audio []int16) ([]WordAlignment, error) {
+	words := strings.Fields(transcript)
+	if err := p.checkTranscript(words); err != nil {
+		return nil, err
+	}
+	if !legacyAPI {
+		return p.alignNative(words, audio)
+	}
+	phones, phoneWords, err := p.phoneWords(words)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]WordAlignment, len(words))
+	for i, word := range words {
+		ret[i] = WordAlignment{Word: word, Phones: make([]PhoneResult, len(phones[i]))}
+		for j, phone := range phones[i] {
+			ret[i].Phones[j].Phone = phone
+		}
+	}
+
+	segs, err := p.alignSegments(phoneWords, audio)
+	if err != nil {
+		return nil, err
+	}
+	if len(segs) != len(phoneWords) {
+		return nil, fmt.Errorf("align error:%w", ErrNoHypothesis)
+	}
+	frate := p.frameRate()
+	for i := range ret {
+		for j := range ret[i].Phones {
+			seg := segs[0]
+			segs = segs[1:]
+			ret[i].Phones[j].Start = float64(seg.StartFrame) / frate
+			ret[i].Phones[j].End = float64(seg.EndFrame+1) / frate
+		}
+		if phones := ret[i].Phones; len(phones) > 0 {
+			ret[i].Start = phones[0].Start
+			ret[i].End = phones[len(phones)-1].End
+		}
+	}
+	return ret, nil
+}
+
+//phoneWords gets the phones of the main pronunciation of every word, and the sequence of words standing for them, adding those missing to the dictionary.
+func (p *PocketSphinx) phoneWords(words []string) ([][]string, []string, error) {
+	phones := make([][]string, len(words))
+	var phoneWords []string
+	for i, word := range words {
+		pron, _ := p.LookupWord(word)
+		phones[i] = strings.Fields(pron)
+		for _, phone := range phones[i] {
+			phoneWord := phoneWordPrefix + phone
+			if _, ok := p.LookupWord(phoneWord); !ok {
+				if err := p.AddWord(phoneWord, phone, false); err != nil {
+					return nil, nil, err
+				}
+			}
+			phoneWords = append(phoneWords, phoneWord)
+		}
+	}
+	return phones, phoneWords, nil
+}
+
+func (p *PocketSphinx) checkTranscript(words []string) error {
+	if p.ps == nil {
+		return ErrClosed
+	}
+	if len(words) == 0 {
+		return fmt.Errorf("align error:%w", ErrEmptyInput)
+	}
+	if missing := p.MissingWords(strings.Join(words, " ")); len(missing) > 0 {
+		return fmt.Errorf("align error:%w:%s", ErrWordNotInDict, strings.Join(missing, " "))
+	}
+	return nil
+}
+
+//alignSegments decodes audio with a linear grammar of words, returning the segments of the words without fillers. The grammar search is removed afterwards.
+func (p *PocketSphinx) alignSegments(words []string, audio []int16) (ret []Segment, err error) {
+	fsg := NewFSG(len(words) + 1)
+	for i, word := range words {
+		fsg.AddTransition(i, i+1, word, 1)
+	}
+	if err := p.SetFSG(alignSearch, fsg); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if uerr := p.UnsetSearch(alignSearch); uerr != nil && err == nil {
+			ret, err = nil, fmt.Errorf("align error:%w", uerr)
+		}
+	}()
+	segs, err := p.decodeWith(alignSearch, audio)
+	if err != nil {
+		return nil, fmt.Errorf("align error:%w", err)
+	}
+	ret = segs[:0]
+	for _, seg := range segs {
+		if !isFiller(seg.Word) {
+			ret = append(ret, seg)
+		}
+	}
+	return ret, nil
+}
+
+//decodeWith decodes audio as a whole utterance with search, returning its segmentation. The active search is restored afterwards.
+func (p *PocketSphinx) decodeWith(search string, audio []int16) ([]Segment, error) {
+	prev := p.GetSearch()
+	if err := p.SetSearch(search); err != nil {
+		return nil, err
+	}
+	defer p.SetSearch(prev)
+
+	if err := p.StartUtt(); err != nil {
+		return nil, err
+	}
+	if _, err := p.processNative(audio, false, true); err != nil {
+		p.EndUtt()
+		return nil, err
+	}
+	if err := p.EndUtt(); err != nil {
+		return nil, err
+	}
+	return p.Segments()
+}
@@ -0,0 +1,12 @@
+//go:build !pocketsphinx5
+
+package pocketsphinx
+
+import (
+	"fmt"
+)
+
+//alignNative returns ErrUnsupported, as the legacy API has no alignment search. Align and AlignPhones decode with a grammar instead.
+func (p *PocketSphinx) alignNative(words []string, audio []int16) ([]WordAlignment, error) {
+	return nil, fmt.Errorf("align error:%w", ErrUnsupported)
+}
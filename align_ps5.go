@@ -0,0 +1,99 @@
+//go:build pocketsphinx5
+
+package pocketsphinx
+
+/*
+#include <pocketsphinx.h>
+#include <pocketsphinx/alignment.h>
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"strings"
+	"unsafe"
+)
+
+//alignNative aligns words with audio with the alignment search of pocketsphinx 5, ps_alignment_t: a first pass finds the times of the words, and a second one those of their phones. The active search is restored afterwards.
+func (p *PocketSphinx) alignNative(words []string, audio []int16) ([]WordAlignment, error) {
+	if err := p.acquire(); err != nil {
+		return nil, err
+	}
+	defer p.release()
+
+	prev := C.CString(p.getSearch())
+	defer C.free(unsafe.Pointer(prev))
+	text := C.CString(strings.Join(words, " "))
+	defer C.free(unsafe.Pointer(text))
+	var ret C.int
+	reason := captureErrors(func() {
+		ret = C.ps_set_align_text(p.ps, text)
+	})
+	if ret != 0 {
+		return nil, searchError("align", alignSearch, ErrBadGrammar, reason)
+	}
+	defer C.ps_set_search(p.ps, prev)
+
+	if err := p.decodeUtt(audio); err != nil {
+		return nil, fmt.Errorf("align error:%w", err)
+	}
+	if C.ps_set_alignment(p.ps, nil) != 0 {
+		return nil, fmt.Errorf("align error:%w", ErrNoHypothesis)
+	}
+	if err := p.decodeUtt(audio); err != nil {
+		return nil, fmt.Errorf("align error:%w", err)
+	}
+	al := C.ps_get_alignment(p.ps)
+	if al == nil {
+		return nil, fmt.Errorf("align error:%w", ErrNoHypothesis)
+	}
+
+	frate := p.frameRate()
+	var start, duration C.int
+	alignments := make([]WordAlignment, 0, len(words))
+	for it := C.ps_alignment_words(al); it != nil; it = C.ps_alignment_iter_next(it) {
+		word := C.GoString(C.ps_alignment_iter_name(it))
+		if isFiller(word) {
+			continue
+		}
+		C.ps_alignment_iter_seg(it, &start, &duration)
+		wa := WordAlignment{
+			Word:  word,
+			Start: float64(start) / frate,
+			End:   float64(start+duration) / frate,
+		}
+		for ph := C.ps_alignment_iter_children(it); ph != nil; ph = C.ps_alignment_iter_next(ph) {
+			C.ps_alignment_iter_seg(ph, &start, &duration)
+			wa.Phones = append(wa.Phones, PhoneResult{
+				Phone: C.GoString(C.ps_alignment_iter_name(ph)),
+				Start: float64(start) / frate,
+				End:   float64(start+duration) / frate,
+			})
+		}
+		alignments = append(alignments, wa)
+	}
+	if len(alignments) != len(words) {
+		return nil, fmt.Errorf("align error:%w", ErrNoHypothesis)
+	}
+	//Report the words of the transcript rather than the alternate pronunciations chosen.
+	for i := range alignments {
+		alignments[i].Word = words[i]
+	}
+	return alignments, nil
+}
+
+//decodeUtt decodes audio as a whole utterance with the active search, while the decoder is acquired.
+func (p *PocketSphinx) decodeUtt(audio []int16) error {
+	if ret := C.ps_start_utt(p.ps); ret != 0 {
+		return fmt.Errorf("start_utt error:%d", ret)
+	}
+	if _, err := p.processRaw(audio, false, true); err != nil {
+		C.ps_end_utt(p.ps)
+		return err
+	}
+	if ret := C.ps_end_utt(p.ps); ret != 0 {
+		return fmt.Errorf("end_utt error:%d", ret)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package pocketsphinx
+
+/*
+#include <pocketsphinx.h>
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"os"
+	"unsafe"
+)
+
+//PhoneResult is a recognized phone with its start and end time in seconds.
+type PhoneResult struct {
+	Phone string  `json:"phone"`
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
+
+//SetAllphone adds a phone recognition search named name, constrained by the phonetic n-gram model lm, or by none if lm is nil. Its hypotheses are sequences of phones rather than words, see GetPhones.
+func (p *PocketSphinx) SetAllphone(name string, lm *NgramModel) error {
+	if err := p.acquire(); err != nil {
+		return err
+	}
+	defer p.release()
+	var clm *C.ngram_model_t
+	if lm != nil {
+		if lm.lm == nil {
+			return fmt.Errorf("allphone %s error:%w:model freed", name, ErrBadLM)
+		}
+		clm = lm.lm
+	}
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	var ret C.int
+	reason := captureErrors(func() {
+		ret = C.ps_set_allphone(p.ps, cname, clm)
+	})
+	if ret != 0 {
+		return searchError("allphone", name, ErrBadLM, reason)
+	}
+	p.phoneSearches[name] = true
+	return nil
+}
+
+//SetAllphoneFile adds a phone recognition search named name, constrained by the phonetic n-gram model in the file at path, or by none if path is empty.
+func (p *PocketSphinx) SetAllphoneFile(name, path string) error {
+	if err := p.acquire(); err != nil {
+		return err
+	}
+	defer p.release()
+	var cpath *C.char
+	if path != "" {
+		if _, err := os.Stat(path); err != nil {
+			return err
+		}
+		cpath = C.CString(path)
+		defer C.free(unsafe.Pointer(cpath))
+	}
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	var ret C.int
+	reason := captureErrors(func() {
+		ret = C.ps_set_allphone_file(p.ps, cname, cpath)
+	})
+	if ret != 0 {
+		return searchError("allphone", name, ErrBadLM, reason)
+	}
+	p.phoneSearches[name] = true
+	return nil
+}
+
+//GetPhones gets the phones of the best hypothesis of a phone recognition search with their times, leaving out silence.
+func (p *PocketSphinx) GetPhones() ([]PhoneResult, error) {
+	segs, err := p.PhoneSegments()
+	if err != nil {
+		return nil, err
+	}
+	frate := p.frameRate()
+	ret := make([]PhoneResult, 0, len(segs))
+	for _, seg := range segs {
+		if seg.Phone == "SIL" {
+			continue
+		}
+		ret = append(ret, PhoneResult{
+			Phone: seg.Phone,
+			Start: float64(seg.StartFrame) / frate,
+			End:   float64(seg.EndFrame+1) / frate,
+		})
+	}
+	return ret, nil
+}
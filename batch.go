@@ -0,0 +1,162 @@
+package pocketsphinx
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+//Batch transcribes every audio file in a directory tree using several decoders in parallel, writing a transcript next to each file or under OutputDir.
+type Batch struct {
+	//Config configures the decoders.
+	Config Config
+	//Workers is the number of decoders. It defaults to the number of CPUs.
+	Workers int
+	//Pattern selects the files to transcribe by base name, as in filepath.Match. It defaults to "*.wav".
+	Pattern string
+	//OutputDir is where transcripts are written, mirroring the layout of the input directory. It defaults to the input directory.
+	OutputDir string
+	//Ext replaces the extension of an audio file to name its transcript. It defaults to ".txt".
+	Ext string
+}
+
+//BatchFile is the outcome of transcribing one file.
+type BatchFile struct {
+	Path       string
+	Output     string
+	Transcript Transcript
+	Elapsed    time.Duration
+	Err        error
+}
+
+//BatchReport is the outcome of a Batch run. Files are in path order.
+type BatchReport struct {
+	Files     []BatchFile
+	Succeeded int
+	Failed    int
+	//Audio is the total duration of the audio transcribed successfully.
+	Audio time.Duration
+	//Elapsed is the wall time of the whole run.
+	Elapsed time.Duration
+}
+
+//RTF gets the real time factor of the run, wall time over audio duration.
+func (r BatchReport) RTF() float64 {
+	if r.Audio == 0 {
+		return 0
+	}
+	return r.Elapsed.Seconds() / r.Audio.Seconds()
+}
+
+//Run transcribes the files under dir. Errors transcribing a file are reported in its BatchFile; an error is only returned if the directory can't be walked or a decoder can't be created.
+func (b *Batch) Run(dir string) (BatchReport, error) {
+	start := time.Now()
+	workers := b.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	pattern := b.Pattern
+	if pattern == "" {
+		pattern = "*.wav"
+	}
+
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if ok, _ := filepath.Match(pattern, d.Name()); ok {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return BatchReport{}, err
+	}
+	sort.Strings(paths)
+	if workers > len(paths) {
+		workers = len(paths)
+	}
+
+	decoders := make([]*PocketSphinx, 0, workers)
+	defer func() {
+		for _, p := range decoders {
+			p.Free()
+		}
+	}()
+	for i := 0; i < workers; i++ {
+		p, err := NewFromConfig(b.Config)
+		if err != nil {
+			return BatchReport{}, err
+		}
+		decoders = append(decoders, p)
+	}
+
+	files := make([]BatchFile, len(paths))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for _, p := range decoders {
+		wg.Add(1)
+		go func(p *PocketSphinx) {
+			defer wg.Done()
+			for i := range jobs {
+				files[i] = b.transcribe(p, dir, paths[i])
+			}
+		}(p)
+	}
+	for i := range paths {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	report := BatchReport{Files: files}
+	for _, f := range files {
+		if f.Err != nil {
+			report.Failed++
+			continue
+		}
+		report.Succeeded++
+		report.Audio += f.Transcript.Duration
+	}
+	report.Elapsed = time.Since(start)
+	return report, nil
+}
+
+func (b *Batch) transcribe(p *PocketSphinx, dir, path string) BatchFile {
+	start := time.Now()
+	f := BatchFile{Path: path, Output: b.outputPath(dir, path)}
+	f.Transcript, f.Err = p.TranscribeFile(path, nil)
+	if f.Err == nil {
+		f.Err = os.MkdirAll(filepath.Dir(f.Output), 0755)
+	}
+	if f.Err == nil {
+		f.Err = os.WriteFile(f.Output, []byte(f.Transcript.Text()+"\n"), 0644)
+	}
+	f.Elapsed = time.Since(start)
+	return f
+}
+
+func (b *Batch) outputPath(dir, path string) string {
+	ext := b.Ext
+	if ext == "" {
+		ext = ".txt"
+	}
+	out := strings.TrimSuffix(path, filepath.Ext(path)) + ext
+	if b.OutputDir == "" {
+		return out
+	}
+	rel, err := filepath.Rel(dir, out)
+	if err != nil {
+		rel = filepath.Base(out)
+	}
+	return filepath.Join(b.OutputDir, rel)
+}
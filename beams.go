@@ -0,0 +1,120 @@
+package pocketsphinx
+
+/*
+#include <pocketsphinx.h>
+*/
+import "C"
+
+import (
+	"fmt"
+)
+
+//Beams are the pruning thresholds of n-gram search, trading accuracy for CPU. Beam widths are probabilities relative to the best path, smaller values keeping more paths alive; MaxWPF and MaxHMMPF cap the number of words and HMMs evaluated per frame, -1 meaning no limit. Zero values keep the current setting.
+type Beams struct {
+	//Beam prunes HMMs in every frame (-beam).
+	Beam float64 `json:"beam,omitempty" yaml:"beam,omitempty"`
+	//WBeam prunes word exits (-wbeam).
+	WBeam float64 `json:"wbeam,omitempty" yaml:"wbeam,omitempty"`
+	//PBeam prunes transitions to the next phone (-pbeam).
+	PBeam float64 `json:"pbeam,omitempty" yaml:"pbeam,omitempty"`
+	//LPBeam prunes transitions to the last phone of words (-lpbeam).
+	LPBeam float64 `json:"lpbeam,omitempty" yaml:"lpbeam,omitempty"`
+	//MaxWPF is the maximum number of distinct words exiting in a frame (-maxwpf).
+	MaxWPF int64 `json:"maxwpf,omitempty" yaml:"maxwpf,omitempty"`
+	//MaxHMMPF is the maximum number of active HMMs in a frame (-maxhmmpf).
+	MaxHMMPF int64 `json:"maxhmmpf,omitempty" yaml:"maxhmmpf,omitempty"`
+}
+
+//BeamPresets are named Beams for common trade-offs. "default" restores the pocketsphinx defaults, "accurate" searches wider for offline transcription, "fast" suits live recognition on desktop CPUs and "embedded" small boards such as the Raspberry Pi.
+var BeamPresets = map[string]Beams{
+	"accurate": {Beam: 1e-60, WBeam: 1e-40, PBeam: 1e-60, LPBeam: 1e-50, MaxWPF: -1, MaxHMMPF: -1},
+	"default":  {Beam: 1e-48, WBeam: 7e-29, PBeam: 1e-48, LPBeam: 1e-40, MaxWPF: -1, MaxHMMPF: 30000},
+	"fast":     {Beam: 1e-30, WBeam: 1e-20, PBeam: 1e-30, LPBeam: 1e-25, MaxWPF: 10, MaxHMMPF: 5000},
+	"embedded": {Beam: 1e-20, WBeam: 1e-15, PBeam: 1e-20, LPBeam: 1e-15, MaxWPF: 5, MaxHMMPF: 2000},
+}
+
+//flags calls f with the flags of the fields of b that are set.
+func (b Beams) flags(f func(key string, value interface{})) {
+	floats := []struct {
+		key string
+		val float64
+	}{{"-beam", b.Beam}, {"-wbeam", b.WBeam}, {"-pbeam", b.PBeam}, {"-lpbeam", b.LPBeam}}
+	for _, fl := range floats {
+		if fl.val != 0 {
+			f(fl.key, fl.val)
+		}
+	}
+	if b.MaxWPF != 0 {
+		f("-maxwpf", b.MaxWPF)
+	}
+	if b.MaxHMMPF != 0 {
+		f("-maxhmmpf", b.MaxHMMPF)
+	}
+}
+
+//WithBeams sets the fields of b that are set.
+func WithBeams(b Beams) Option {
+	return func(psConfig *C.cmd_ln_t) {
+		b.flags(func(key string, value interface{}) {
+			switch v := value.(type) {
+			case float64:
+				setFloatParam(psConfig, key, v)
+			case int64:
+				setIntParam(psConfig, key, v)
+			}
+		})
+	}
+}
+
+//SetBeams copies the fields of b that are set to cfg.
+func (cfg *Config) SetBeams(b Beams) {
+	set := func(dst *float64, val float64) {
+		if val != 0 {
+			*dst = val
+		}
+	}
+	set(&cfg.Beam, b.Beam)
+	set(&cfg.WBeam, b.WBeam)
+	set(&cfg.PBeam, b.PBeam)
+	set(&cfg.LPBeam, b.LPBeam)
+	if b.MaxWPF != 0 {
+		cfg.MaxWPF = b.MaxWPF
+	}
+	if b.MaxHMMPF != 0 {
+		cfg.MaxHMMPF = b.MaxHMMPF
+	}
+}
+
+//SetBeams changes the fields of b that are set and reinitializes the decoder to apply them, which drops the searches added at runtime.
+func (p *PocketSphinx) SetBeams(b Beams) error {
+	var err error
+	b.flags(func(key string, value interface{}) {
+		if err == nil {
+			err = p.SetOption(key, value)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	return p.Reinit()
+}
+
+//ApplyPreset applies the Beams of BeamPresets named name, as SetBeams does.
+func (p *PocketSphinx) ApplyPreset(name string) error {
+	b, ok := BeamPresets[name]
+	if !ok {
+		return fmt.Errorf("unknown beam preset:%s", name)
+	}
+	return p.SetBeams(b)
+}
+
+//SetPasses enables or disables the fwdflat and bestpath passes of n-gram search, e.g. turning them off while low latency matters more than accuracy, and reinitializes the decoder to apply them as SetBeams does. See the Fwdflat and Bestpath fields of Config for their tradeoffs.
+func (p *PocketSphinx) SetPasses(fwdflat, bestpath bool) error {
+	if err := p.SetOption("-fwdflat", fwdflat); err != nil {
+		return err
+	}
+	if err := p.SetOption("-bestpath", bestpath); err != nil {
+		return err
+	}
+	return p.Reinit()
+}
@@ -0,0 +1,76 @@
+package pocketsphinx
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+//The benchmarks measure the real time factor and allocations of decoding testdata/speech.wav through keyword spotting, grammar and language model searches, so that performance regressions of the binding, such as extra copies or cgo calls, show up when comparing runs with benchstat:
+//
+//	go test -run '^$' -bench . -count 10 > new.txt
+//	benchstat old.txt new.txt
+//
+//speech.wav is synthetic, vowel-like bursts of pitch harmonics rather than real speech, so only the cost of decoding is meaningful, not the hypotheses. The searches use commands.dict, commands.gram and commands.lm, with the en-us acoustic model from POCKETSPHINX_HMM, or installed with the library; the benchmarks are skipped without it.
+
+//testHMM gets the acoustic model directory of the benchmarks and of the tests decoding audio, skipping them without one.
+func testHMM(tb testing.TB) string {
+	if hmm := os.Getenv("POCKETSPHINX_HMM"); hmm != "" {
+		return hmm
+	}
+	if out, err := exec.Command("pkg-config", "--variable=modeldir", "pocketsphinx").Output(); err == nil {
+		hmm := filepath.Join(strings.TrimSpace(string(out)), "en-us", "en-us")
+		if _, err := os.Stat(hmm); err == nil {
+			return hmm
+		}
+	}
+	tb.Skip("no en-us acoustic model, set POCKETSPHINX_HMM")
+	return ""
+}
+
+func benchmarkSearch(b *testing.B, search Option) {
+	f, err := os.Open("testdata/speech.wav")
+	if err != nil {
+		b.Fatal(err)
+	}
+	format, audio, err := ReadWAV(f)
+	f.Close()
+	if err != nil {
+		b.Fatal(err)
+	}
+	p, err := New(WithHMM(testHMM(b)), WithDict("testdata/commands.dict"), WithString("-logfn", os.DevNull), search)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer p.Free()
+	//Decode once first, so lazily loaded models don't count.
+	if _, err := p.ProcessUtt(audio, 1); err != nil && !errors.Is(err, ErrNoHypothesis) {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := p.ProcessUtt(audio, 1); err != nil && !errors.Is(err, ErrNoHypothesis) {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+	seconds := float64(len(audio)) / float64(format.SampleRate)
+	b.ReportMetric(b.Elapsed().Seconds()/float64(b.N)/seconds, "rtf")
+}
+
+func BenchmarkKws(b *testing.B) {
+	benchmarkSearch(b, WithKeyphrase("oh mighty computer"))
+}
+
+func BenchmarkJSGF(b *testing.B) {
+	benchmarkSearch(b, WithJSGFFile("testdata/commands.gram"))
+}
+
+func BenchmarkLM(b *testing.B) {
+	benchmarkSearch(b, WithLM("testdata/commands.lm"))
+}
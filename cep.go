@@ -0,0 +1,92 @@
+package pocketsphinx
+
+/*
+#include <pocketsphinx.h>
+#include <stdlib.h>
+static int cep_size(ps_decoder_t *ps){
+#ifdef POCKETSPHINX5
+    return ps_config_int(ps_get_config(ps), "ceplen");
+#else
+    feat_t *feat = ps_get_feat(ps);
+    if (feat == NULL)
+        return 0;
+    return feat->cepsize;
+#endif
+}
+static int process_cep(ps_decoder_t *ps, float const *data, int nframes, int veclen, int no_search, int full_utt){
+    mfcc_t *buf = malloc(nframes * veclen * sizeof(mfcc_t));
+    mfcc_t **cep = malloc(nframes * sizeof(mfcc_t *));
+    int i, ret = -1;
+    if (buf != NULL && cep != NULL) {
+        for (i = 0; i < nframes * veclen; ++i)
+            buf[i] = FLOAT2MFCC(data[i]);
+        for (i = 0; i < nframes; ++i)
+            cep[i] = buf + i * veclen;
+        ret = ps_process_cep(ps, cep, nframes, no_search, full_utt);
+    }
+    free(cep);
+    free(buf);
+    return ret;
+}
+*/
+import "C"
+
+import (
+	"fmt"
+)
+
+//ProcessCep processes precomputed cepstral frames, e.g. computed by a FeatureExtractor or read from a .mfc file, instead of audio, so repeated experiments over the same data skip feature extraction. Frames must have been computed with the front end parameters of the decoder, and have as many coefficients as its cepstral vectors. noSearch and fullUtt are as for ProcessRaw; with fullUtt, cepstral mean normalization uses the mean of the whole utterance.
+//It returns the number of frames searched.
+func (p *PocketSphinx) ProcessCep(frames [][]float32, noSearch, fullUtt bool) (int, error) {
+	if err := p.acquire(); err != nil {
+		return 0, err
+	}
+	defer p.release()
+
+	if len(frames) == 0 {
+		return 0, ErrEmptyInput
+	}
+	veclen := int(C.cep_size(p.ps))
+	if veclen == 0 {
+		return 0, fmt.Errorf("process_cep error:decoder has no feature computation")
+	}
+	data := make([]C.float, 0, len(frames)*veclen)
+	for i, frame := range frames {
+		if len(frame) != veclen {
+			return 0, fmt.Errorf("process_cep error:frame %d has %d coefficients, want %d", i, len(frame), veclen)
+		}
+		for _, v := range frame {
+			data = append(data, C.float(v))
+		}
+	}
+	processed := C.process_cep(p.ps, &data[0], C.int(len(frames)), C.int(veclen), C.int(bool2int(noSearch)), C.int(bool2int(fullUtt)))
+	if processed < 0 {
+		decodeError("process_cep")
+		return 0, fmt.Errorf("process_cep error")
+	}
+	return int(processed), nil
+}
+
+//DecodeMFCFile decodes the sphinx feature file at path as a single utterance, see ReadMFC.
+func (p *PocketSphinx) DecodeMFCFile(path string) (Result, error) {
+	if err := p.acquire(); err != nil {
+		return Result{}, err
+	}
+	size := int(C.cep_size(p.ps))
+	p.release()
+	frames, err := LoadMFC(path, size)
+	if err != nil {
+		return Result{}, err
+	}
+	if err := p.StartUtt(); err != nil {
+		return Result{}, err
+	}
+	if _, err := p.ProcessCep(frames, false, true); err != nil {
+		p.EndUtt()
+		return Result{}, err
+	}
+	if err := p.EndUtt(); err != nil {
+		return Result{}, err
+	}
+	return p.GetHyp()
+}
@@ -0,0 +1,19 @@
+//go:build pocketsphinx5
+
+package pocketsphinx
+
+//The pocketsphinx 5 API, to which ps5compat.h maps the legacy calls made by the binding. CMN and SetCMN use ps_get_cmn and ps_set_cmn. The front end and AGC internals used by FeatureExtractor and AGCEstimate aren't public in pocketsphinx 5, so those return ErrUnsupported, and NewFromArgs doesn't honor -argfile. With the nopkgconfig tag, the library is located as for the legacy API, from the cgo environment variables.
+
+/*
+#cgo !nopkgconfig pkg-config: pocketsphinx
+#cgo nopkgconfig LDFLAGS: -lpocketsphinx -lm
+#cgo CFLAGS: -DPOCKETSPHINX5 -include ${SRCDIR}/ps5compat.h
+*/
+import "C"
+
+const (
+	pocketsphinxVersion = "5"
+	//sphinxbaseVersion is empty, as sphinxbase is part of pocketsphinx 5.
+	sphinxbaseVersion = ""
+	legacyAPI         = false
+)
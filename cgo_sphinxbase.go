@@ -0,0 +1,22 @@
+//go:build !pocketsphinx5
+
+package pocketsphinx
+
+//The legacy pocketsphinx 0.8/5prealpha API, split between pocketsphinx and sphinxbase.
+//
+//The libraries are located with pkg-config. The pocketsphinx_vendor tag links static libraries built from source instead, see cgo_vendor.go. Without pkg-config, e.g. on Windows with MSYS2 or vcpkg, build with the nopkgconfig tag, and pass the include directories, with their pocketsphinx and sphinxbase subdirectories, and the library directory through the cgo environment variables, for instance from POCKETSPHINX_CFLAGS and POCKETSPHINX_LIBS:
+//
+//	CGO_CFLAGS="$POCKETSPHINX_CFLAGS" CGO_LDFLAGS="$POCKETSPHINX_LIBS" go build -tags nopkgconfig
+
+/*
+#cgo !nopkgconfig,!pocketsphinx_vendor pkg-config: pocketsphinx sphinxbase
+#cgo nopkgconfig,!pocketsphinx_vendor LDFLAGS: -lpocketsphinx -lsphinxbase -lm
+*/
+import "C"
+
+const (
+	pocketsphinxVersion = "5prealpha"
+	sphinxbaseVersion   = "5prealpha"
+	//legacyAPI is set when built against the sphinxbase API, whose front end, AGC and -argfile parsing the binding uses.
+	legacyAPI = true
+)
@@ -0,0 +1,14 @@
+//go:build pocketsphinx_vendor && !pocketsphinx5
+
+package pocketsphinx
+
+//The pocketsphinx_vendor tag links static sphinxbase and pocketsphinx libraries built from source into third_party/install, for a binary that doesn't depend on the C libraries being installed, e.g. in containers or when cross-compiling. The sources aren't part of the repository: go generate -tags pocketsphinx_vendor downloads them from SourceForge, which needs network access unless the release tarballs were put in third_party/src beforehand, checks them against the sha256 pinned in third_party/SHA256SUMS, and builds them first. The go build itself then runs offline.
+
+//go:generate sh third_party/build.sh
+
+/*
+#cgo CFLAGS: -I${SRCDIR}/third_party/install/include -I${SRCDIR}/third_party/install/include/pocketsphinx -I${SRCDIR}/third_party/install/include/sphinxbase
+#cgo LDFLAGS: ${SRCDIR}/third_party/install/lib/libpocketsphinx.a ${SRCDIR}/third_party/install/lib/libsphinxbase.a -lm
+#cgo linux LDFLAGS: -lpthread
+*/
+import "C"
@@ -0,0 +1,143 @@
+package pocketsphinx
+
+/*
+#include <pocketsphinx.h>
+#ifdef POCKETSPHINX5
+#include <stdio.h>
+#include <stdlib.h>
+#include <string.h>
+static int cmn_veclen(ps_decoder_t *ps){
+    char const *cmn = ps_get_cmn(ps, FALSE);
+    int n = 1;
+    if (cmn == NULL || *cmn == '\0')
+        return 0;
+    for (; *cmn != '\0'; ++cmn)
+        if (*cmn == ',')
+            ++n;
+    return n;
+}
+static void cmn_get(ps_decoder_t *ps, float *vec){
+    char const *cmn = ps_get_cmn(ps, FALSE);
+    char *end;
+    int i;
+    for (i = 0; cmn != NULL && *cmn != '\0'; ++i) {
+        vec[i] = strtod(cmn, &end);
+        cmn = (*end == ',') ? end + 1 : end;
+    }
+}
+static void cmn_set(ps_decoder_t *ps, float const *vec){
+    char buf[64 * 16];
+    size_t len = 0;
+    int i, n = cmn_veclen(ps);
+    buf[0] = '\0';
+    for (i = 0; i < n && i < 64; ++i)
+        len += snprintf(buf + len, sizeof(buf) - len, i ? ",%.2f" : "%.2f", vec[i]);
+    ps_set_cmn(ps, buf);
+}
+#else
+static int cmn_veclen(ps_decoder_t *ps){
+    feat_t *feat = ps_get_feat(ps);
+    if (feat == NULL || feat->cmn_struct == NULL)
+        return 0;
+    return feat->cmn_struct->veclen;
+}
+static void cmn_get(ps_decoder_t *ps, float *vec){
+    cmn_t *cmn = ps_get_feat(ps)->cmn_struct;
+    int i;
+    for (i = 0; i < cmn->veclen; ++i)
+        vec[i] = MFCC2FLOAT(cmn->cmn_mean[i]);
+}
+static void cmn_set(ps_decoder_t *ps, float const *vec){
+    cmn_t *cmn = ps_get_feat(ps)->cmn_struct;
+    mfcc_t mean[64];
+    int i;
+    for (i = 0; i < cmn->veclen && i < 64; ++i)
+        mean[i] = FLOAT2MFCC(vec[i]);
+    cmn_prior_set(cmn, mean);
+}
+#endif
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+//maxCepSize is the size of the largest cepstral vectors handled by the CMN functions.
+const maxCepSize = 64
+
+//CMN gets the current cepstral mean used for normalization, which the front end updates as it processes audio when -cmn is "live" or "prior".
+func (p *PocketSphinx) CMN() ([]float64, error) {
+	if err := p.acquire(); err != nil {
+		return nil, err
+	}
+	defer p.release()
+	n := int(C.cmn_veclen(p.ps))
+	if n == 0 {
+		return nil, errors.New("cmn error:no cepstral mean normalization")
+	}
+	vec := make([]C.float, n)
+	C.cmn_get(p.ps, &vec[0])
+	ret := make([]float64, n)
+	for i, v := range vec {
+		ret[i] = float64(v)
+	}
+	return ret, nil
+}
+
+//SetCMN sets the cepstral mean, e.g. to one saved with CMN for a known microphone, so recognition is accurate from the first utterance rather than once the mean adapted. It must have as many values as the cepstral vectors, 13 for most models.
+func (p *PocketSphinx) SetCMN(mean []float64) error {
+	if err := p.acquire(); err != nil {
+		return err
+	}
+	defer p.release()
+	return p.setCMN(mean)
+}
+
+func (p *PocketSphinx) setCMN(mean []float64) error {
+	n := int(C.cmn_veclen(p.ps))
+	if n == 0 {
+		return errors.New("cmn error:no cepstral mean normalization")
+	}
+	if len(mean) != n || n > maxCepSize {
+		return fmt.Errorf("cmn error:got %d values, want %d", len(mean), n)
+	}
+	vec := make([]C.float, n)
+	for i, v := range mean {
+		vec[i] = C.float(v)
+	}
+	C.cmn_set(p.ps, &vec[0])
+	return nil
+}
+
+//ResetCMN resets the cepstral mean to its initial value (-cmninit), e.g. when the speaker or channel changes, so that adaptation to the previous one doesn't carry over.
+func (p *PocketSphinx) ResetCMN() error {
+	if err := p.acquire(); err != nil {
+		return err
+	}
+	defer p.release()
+	mean, err := parseCMNInit(getStringParam(C.ps_get_config(p.ps), "-cmninit"), int(C.cmn_veclen(p.ps)))
+	if err != nil {
+		return err
+	}
+	return p.setCMN(mean)
+}
+
+//parseCMNInit parses the comma separated values of -cmninit into a vector of n values, the missing ones being 0.
+func parseCMNInit(init string, n int) ([]float64, error) {
+	mean := make([]float64, n)
+	for i, field := range strings.Split(init, ",") {
+		if i >= n || strings.TrimSpace(field) == "" {
+			break
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil {
+			return nil, fmt.Errorf("cmninit error:%v", err)
+		}
+		mean[i] = v
+	}
+	return mean, nil
+}
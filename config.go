@@ -0,0 +1,192 @@
+package pocketsphinx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//Config holds typed decoder flags. String fields left empty and numeric fields left at zero keep the pocketsphinx default; boolean fields are pointers so that an explicit false can be told apart from unset, use Bool to fill them.
+//The json and yaml names match the pocketsphinx flag names without the leading dash.
+type Config struct {
+	//Model files.
+	HMM       string `json:"hmm,omitempty" yaml:"hmm,omitempty"`
+	Dict      string `json:"dict,omitempty" yaml:"dict,omitempty"`
+	FDict     string `json:"fdict,omitempty" yaml:"fdict,omitempty"`
+	LM        string `json:"lm,omitempty" yaml:"lm,omitempty"`
+	LMCtl     string `json:"lmctl,omitempty" yaml:"lmctl,omitempty"`
+	LMName    string `json:"lmname,omitempty" yaml:"lmname,omitempty"`
+	JSGF      string `json:"jsgf,omitempty" yaml:"jsgf,omitempty"`
+	TopRule   string `json:"toprule,omitempty" yaml:"toprule,omitempty"`
+	FSG       string `json:"fsg,omitempty" yaml:"fsg,omitempty"`
+	Kws       string `json:"kws,omitempty" yaml:"kws,omitempty"`
+	Keyphrase string `json:"keyphrase,omitempty" yaml:"keyphrase,omitempty"`
+	Allphone  string `json:"allphone,omitempty" yaml:"allphone,omitempty"`
+	//MMap memory maps the mixture weights and binary language models instead of reading them into memory, so their pages are shared between decoders and processes and only loaded as they are used. It is on by default.
+	MMap *bool `json:"mmap,omitempty" yaml:"mmap,omitempty"`
+
+	//Front end.
+	SampleRate  float64 `json:"samprate,omitempty" yaml:"samprate,omitempty"`
+	InputEndian string  `json:"input_endian,omitempty" yaml:"input_endian,omitempty"`
+	NFFT        int64   `json:"nfft,omitempty" yaml:"nfft,omitempty"`
+	Dither      *bool   `json:"dither,omitempty" yaml:"dither,omitempty"`
+	AGC         AGCMode `json:"agc,omitempty" yaml:"agc,omitempty"`
+	AGCThresh   float64 `json:"agcthresh,omitempty" yaml:"agcthresh,omitempty"`
+	CMN         string  `json:"cmn,omitempty" yaml:"cmn,omitempty"`
+	CMNInit     string  `json:"cmninit,omitempty" yaml:"cmninit,omitempty"`
+	//RemoveNoise subtracts an estimate of the stationary background noise, such as fans or hum, from the spectrum. It is on by default.
+	RemoveNoise *bool `json:"remove_noise,omitempty" yaml:"remove_noise,omitempty"`
+	//RemoveSilence drops the frames classified as silence by voice activity detection before search. It is on by default.
+	RemoveSilence *bool `json:"remove_silence,omitempty" yaml:"remove_silence,omitempty"`
+	//VADThreshold is how far above the noise floor, in dB, a frame must be to count as speech, 2 by default. Raise it for far-field microphones in noisy rooms, where noise is otherwise taken for speech.
+	VADThreshold float64 `json:"vad_threshold,omitempty" yaml:"vad_threshold,omitempty"`
+	//VADPrespeech is the number of frames kept before the start of speech, 20 by default, so soft word onsets aren't cut.
+	VADPrespeech int64 `json:"vad_prespeech,omitempty" yaml:"vad_prespeech,omitempty"`
+	//VADPostspeech is the number of silence frames after which speech is considered ended, 50 by default. Raise it for speakers who pause within a sentence, lower it for a snappier end of utterance.
+	VADPostspeech int64 `json:"vad_postspeech,omitempty" yaml:"vad_postspeech,omitempty"`
+	//VADStartspeech is the number of speech frames in a row needed to detect the start of speech, 10 by default. Raise it to ignore clicks and short bursts of noise.
+	VADStartspeech int64 `json:"vad_startspeech,omitempty" yaml:"vad_startspeech,omitempty"`
+
+	//Search.
+	Beam       float64 `json:"beam,omitempty" yaml:"beam,omitempty"`
+	WBeam      float64 `json:"wbeam,omitempty" yaml:"wbeam,omitempty"`
+	PBeam      float64 `json:"pbeam,omitempty" yaml:"pbeam,omitempty"`
+	LPBeam     float64 `json:"lpbeam,omitempty" yaml:"lpbeam,omitempty"`
+	LPOnlyBeam float64 `json:"lponlybeam,omitempty" yaml:"lponlybeam,omitempty"`
+	MaxWPF     int64   `json:"maxwpf,omitempty" yaml:"maxwpf,omitempty"`
+	MaxHMMPF   int64   `json:"maxhmmpf,omitempty" yaml:"maxhmmpf,omitempty"`
+	//Fwdtree enables the first, tree structured pass of n-gram search, which is the only one producing partial hypotheses. It is on by default.
+	Fwdtree *bool `json:"fwdtree,omitempty" yaml:"fwdtree,omitempty"`
+	//Fwdflat enables a second, flat lexicon pass over the words found by the first one at the end of the utterance. It is on by default and improves accuracy by a few percent, at the cost of latency growing with the length of the utterance.
+	Fwdflat *bool `json:"fwdflat,omitempty" yaml:"fwdflat,omitempty"`
+	//Bestpath enables a final search of the word lattice, which computes posterior probabilities and confidences. It is on by default and cheap compared to Fwdflat; turning it off leaves word probabilities at 1.
+	Bestpath     *bool   `json:"bestpath,omitempty" yaml:"bestpath,omitempty"`
+	KwsThreshold float64 `json:"kws_threshold,omitempty" yaml:"kws_threshold,omitempty"`
+	KwsDelay     int64   `json:"kws_delay,omitempty" yaml:"kws_delay,omitempty"`
+	KwsPLP       float64 `json:"kws_plp,omitempty" yaml:"kws_plp,omitempty"`
+
+	//Language model weights.
+	LW       float64 `json:"lw,omitempty" yaml:"lw,omitempty"`
+	WIP      float64 `json:"wip,omitempty" yaml:"wip,omitempty"`
+	PIP      float64 `json:"pip,omitempty" yaml:"pip,omitempty"`
+	SilProb  float64 `json:"silprob,omitempty" yaml:"silprob,omitempty"`
+	FillProb float64 `json:"fillprob,omitempty" yaml:"fillprob,omitempty"`
+	Ascale   float64 `json:"ascale,omitempty" yaml:"ascale,omitempty"`
+}
+
+//Bool returns a pointer to b, for filling the boolean fields of Config.
+func Bool(b bool) *bool {
+	return &b
+}
+
+//LoadConfigFile reads a Config from a JSON or YAML file, chosen by the .json, .yaml or .yml extension. Unknown flags are reported as errors.
+func LoadConfigFile(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&cfg)
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(&cfg)
+	default:
+		return cfg, fmt.Errorf("config %s error:unknown format", path)
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("config %s error:%v", path, err)
+	}
+	return cfg, nil
+}
+
+//NewFromConfig creates PocketSphinx instance configured by cfg.
+func NewFromConfig(cfg Config) (*PocketSphinx, error) {
+	return New(cfg.Options()...)
+}
+
+//Options translates the fields that are set into Options, so a Config can be combined with other options in New.
+func (cfg Config) Options() []Option {
+	var opts []Option
+	str := func(key, val string) {
+		if val != "" {
+			opts = append(opts, WithString(key, val))
+		}
+	}
+	integer := func(key string, val int64) {
+		if val != 0 {
+			opts = append(opts, WithInt(key, val))
+		}
+	}
+	float := func(key string, val float64) {
+		if val != 0 {
+			opts = append(opts, WithFloat(key, val))
+		}
+	}
+	boolean := func(key string, val *bool) {
+		if val != nil {
+			opts = append(opts, WithBool(key, *val))
+		}
+	}
+
+	str("-hmm", cfg.HMM)
+	str("-dict", cfg.Dict)
+	str("-fdict", cfg.FDict)
+	str("-lm", cfg.LM)
+	str("-lmctl", cfg.LMCtl)
+	str("-lmname", cfg.LMName)
+	str("-jsgf", cfg.JSGF)
+	str("-toprule", cfg.TopRule)
+	str("-fsg", cfg.FSG)
+	str("-kws", cfg.Kws)
+	str("-keyphrase", cfg.Keyphrase)
+	str("-allphone", cfg.Allphone)
+	boolean("-mmap", cfg.MMap)
+
+	float("-samprate", cfg.SampleRate)
+	str("-input_endian", cfg.InputEndian)
+	integer("-nfft", cfg.NFFT)
+	boolean("-dither", cfg.Dither)
+	str("-agc", string(cfg.AGC))
+	float("-agcthresh", cfg.AGCThresh)
+	str("-cmn", cfg.CMN)
+	str("-cmninit", cfg.CMNInit)
+	boolean("-remove_noise", cfg.RemoveNoise)
+	boolean("-remove_silence", cfg.RemoveSilence)
+	float("-vad_threshold", cfg.VADThreshold)
+	integer("-vad_prespeech", cfg.VADPrespeech)
+	integer("-vad_postspeech", cfg.VADPostspeech)
+	integer("-vad_startspeech", cfg.VADStartspeech)
+
+	float("-beam", cfg.Beam)
+	float("-wbeam", cfg.WBeam)
+	float("-pbeam", cfg.PBeam)
+	float("-lpbeam", cfg.LPBeam)
+	float("-lponlybeam", cfg.LPOnlyBeam)
+	integer("-maxwpf", cfg.MaxWPF)
+	integer("-maxhmmpf", cfg.MaxHMMPF)
+	boolean("-fwdtree", cfg.Fwdtree)
+	boolean("-fwdflat", cfg.Fwdflat)
+	boolean("-bestpath", cfg.Bestpath)
+	float("-kws_threshold", cfg.KwsThreshold)
+	integer("-kws_delay", cfg.KwsDelay)
+	float("-kws_plp", cfg.KwsPLP)
+
+	float("-lw", cfg.LW)
+	float("-wip", cfg.WIP)
+	float("-pip", cfg.PIP)
+	float("-silprob", cfg.SilProb)
+	float("-fillprob", cfg.FillProb)
+	float("-ascale", cfg.Ascale)
+
+	return opts
+}
@@ -0,0 +1,137 @@
+package pocketsphinx
+
+import (
+	"sort"
+	"strings"
+)
+
+//Slot is a word position of a confusion network, with the alternatives competing for it ranked by posterior probability. An alternative with an empty Word is the probability that no word was spoken there.
+type Slot struct {
+	StartFrame   int           `json:"start_frame"`
+	EndFrame     int           `json:"end_frame"`
+	Alternatives []Alternative `json:"alternatives"`
+}
+
+//Alternative is a word hypothesis in a Slot.
+type Alternative struct {
+	Word      string  `json:"word"`
+	Posterior float64 `json:"posterior"`
+}
+
+//minSlotOverlap is how much of the union of their spans a link and a slot must share for the link to join the slot.
+const minSlotOverlap = 0.5
+
+//ConfusionNetwork clusters the links of the lattice into time aligned slots, also known as a sausage, after computing posteriors with acoustic scale ascale. Filler words are left out. Slots are in time order.
+func (l *Lattice) ConfusionNetwork(ascale float64) []Slot {
+	l.Posterior(ascale)
+
+	type arc struct {
+		word       string
+		start, end int
+		post       float64
+	}
+	var arcs []arc
+	for _, n := range l.Nodes() {
+		for _, k := range n.Exits() {
+			word := k.BaseWord()
+			if isFiller(word) {
+				continue
+			}
+			start, end := k.Times()
+			arcs = append(arcs, arc{word: word, start: start, end: end, post: k.Posterior()})
+		}
+	}
+	sort.SliceStable(arcs, func(i, j int) bool {
+		return arcs[i].post > arcs[j].post
+	})
+
+	var slots []Slot
+	for _, a := range arcs {
+		best, bestOverlap := -1, 0.0
+		for i, s := range slots {
+			ov := overlap(a.start, a.end, s.StartFrame, s.EndFrame)
+			if ov > bestOverlap {
+				best, bestOverlap = i, ov
+			}
+		}
+		if best < 0 || bestOverlap < minSlotOverlap {
+			slots = append(slots, Slot{
+				StartFrame:   a.start,
+				EndFrame:     a.end,
+				Alternatives: []Alternative{{Word: a.word, Posterior: a.post}},
+			})
+			continue
+		}
+		slots[best].add(a.word, a.post)
+	}
+
+	for i := range slots {
+		slots[i].finish()
+	}
+	sort.Slice(slots, func(i, j int) bool {
+		return slots[i].StartFrame < slots[j].StartFrame
+	})
+	return slots
+}
+
+func (s *Slot) add(word string, post float64) {
+	for i := range s.Alternatives {
+		if s.Alternatives[i].Word == word {
+			s.Alternatives[i].Posterior += post
+			return
+		}
+	}
+	s.Alternatives = append(s.Alternatives, Alternative{Word: word, Posterior: post})
+}
+
+//finish ranks the alternatives and accounts for the remaining probability mass as a deletion.
+func (s *Slot) finish() {
+	total := 0.0
+	for i := range s.Alternatives {
+		if s.Alternatives[i].Posterior > 1 {
+			s.Alternatives[i].Posterior = 1
+		}
+		total += s.Alternatives[i].Posterior
+	}
+	if total < 1 {
+		s.Alternatives = append(s.Alternatives, Alternative{Posterior: 1 - total})
+	}
+	sort.SliceStable(s.Alternatives, func(i, j int) bool {
+		return s.Alternatives[i].Posterior > s.Alternatives[j].Posterior
+	})
+}
+
+//Best gets the most probable alternative of the slot.
+func (s Slot) Best() Alternative {
+	if len(s.Alternatives) == 0 {
+		return Alternative{}
+	}
+	return s.Alternatives[0]
+}
+
+//overlap returns the fraction of the union of two frame spans that they share.
+func overlap(start1, end1, start2, end2 int) float64 {
+	lo, hi := start1, end1
+	if start2 > lo {
+		lo = start2
+	}
+	if end2 < hi {
+		hi = end2
+	}
+	if hi < lo {
+		return 0
+	}
+	ulo, uhi := start1, end1
+	if start2 < ulo {
+		ulo = start2
+	}
+	if end2 > uhi {
+		uhi = end2
+	}
+	return float64(hi-lo+1) / float64(uhi-ulo+1)
+}
+
+//isFiller reports whether word is a sentence marker, silence or noise word rather than speech.
+func isFiller(word string) bool {
+	return strings.HasPrefix(word, "<") || strings.HasPrefix(word, "[") || strings.HasPrefix(word, "++")
+}
@@ -0,0 +1,86 @@
+package pocketsphinx
+
+/*
+#include <pocketsphinx.h>
+#include <stdio.h>
+#include <stdlib.h>
+long decode_raw_file(ps_decoder_t *ps, char const *path){
+    FILE *fh;
+    long n;
+    if ((fh = fopen(path, "rb")) == NULL)
+        return -2;
+    n = ps_decode_raw(ps, fh, -1);
+    fclose(fh);
+    return n;
+}
+char const *decode_utterance(ps_decoder_t *ps, int16 const *data, size_t n_samples, int32 *score, int32 *prob, double *conf, int *stage){
+    char const *hyp;
+    *stage = 0;
+    if (ps_start_utt(ps) < 0)
+        return NULL;
+    *stage = 1;
+    if (ps_process_raw(ps, data, n_samples, FALSE, TRUE) < 0) {
+        ps_end_utt(ps);
+        return NULL;
+    }
+    *stage = 2;
+    if (ps_end_utt(ps) < 0)
+        return NULL;
+    *stage = 3;
+    if ((hyp = ps_get_hyp(ps, score)) == NULL)
+        return NULL;
+    *prob = ps_get_prob(ps);
+    *conf = logmath_exp(ps_get_logmath(ps), *prob);
+    return hyp;
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+//DecodeRawFile decodes the file at path, containing headerless single channel, 16-bit pcm audio in the machine's byte order, as a single utterance. The audio is read by pocketsphinx itself rather than loaded into Go memory.
+func (p *PocketSphinx) DecodeRawFile(path string) (Result, error) {
+	if err := p.acquire(); err != nil {
+		return Result{}, err
+	}
+	cpath := C.CString(path)
+	defer C.free(unsafe.Pointer(cpath))
+	n := C.decode_raw_file(p.ps, cpath)
+	p.release()
+	switch {
+	case n == -2:
+		return Result{}, fmt.Errorf("decode_raw error:can't open %s", path)
+	case n < 0:
+		return Result{}, fmt.Errorf("decode_raw error:%d", n)
+	}
+	return p.GetHyp()
+}
+
+//decodeStages names the steps of decode_utterance for error messages.
+var decodeStages = []string{"start_utt", "process_raw", "end_utt"}
+
+//DecodeUtterance decodes raw as a single utterance and gets the best hypothesis in one cgo call, which is cheaper than ProcessUtt for short command audio. Words are not filled in, use Segments for them.
+func (p *PocketSphinx) DecodeUtterance(raw []int16) (Result, error) {
+	if err := p.acquire(); err != nil {
+		return Result{}, err
+	}
+	defer p.release()
+
+	if len(raw) == 0 {
+		return Result{}, ErrEmptyInput
+	}
+	var score, prob C.int32
+	var conf C.double
+	var stage C.int
+	hyp := C.decode_utterance(p.ps, (*C.int16)(unsafe.Pointer(&raw[0])), C.size_t(len(raw)), &score, &prob, &conf, &stage)
+	if hyp == nil {
+		if int(stage) < len(decodeStages) {
+			return Result{}, fmt.Errorf("%s error", decodeStages[stage])
+		}
+		return Result{}, ErrNoHypothesis
+	}
+	return Result{Text: C.GoString(hyp), Score: int64(score), Prob: int64(prob), Confidence: float64(conf)}, nil
+}
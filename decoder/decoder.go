@@ -0,0 +1,107 @@
+//Package decoder defines the Decoder interface implemented by *pocketsphinx.PocketSphinx, and the results it returns, in pure Go. Code depending only on Decoder can import this package rather than pocketsphinx, so that its tests build without the C library or models, using FakeDecoder:
+//
+//	d := &decoder.FakeDecoder{Results: []decoder.Result{{Text: "turn the lights on"}}}
+//	err := handleCommand(d, audio)
+//
+//The pocketsphinx package aliases the types and errors defined here, so they can be used through either package.
+package decoder
+
+import (
+	"errors"
+	"strings"
+)
+
+//ErrNoHypothesis is returned when the decoder has no hypothesis for the utterance, e.g. because it contained no speech.
+var ErrNoHypothesis = errors.New("no hypothesis")
+
+//ErrClosed is returned when a decoder is used after Free or Close.
+var ErrClosed = errors.New("decoder closed")
+
+//ErrUnknownSearch is returned when activating or removing a search that doesn't exist.
+var ErrUnknownSearch = errors.New("unknown search")
+
+//ErrEmptyInput is returned when an empty buffer of audio is passed for processing.
+var ErrEmptyInput = errors.New("empty input")
+
+//Result is a speech recognition result
+type Result struct {
+	Text  string `json:"text"`
+	Score int64  `json:"score"`
+	Prob  int64  `json:"prob"`
+
+	//Confidence is Prob converted from the log domain to a probability between 0 and 1.
+	Confidence float64 `json:"confidence"`
+
+	Segments []Segment    `json:"segments,omitempty"`
+	Words    []WordResult `json:"words,omitempty"`
+}
+
+//Segment is a word of a hypothesis with its position in the utterance, in frames, and its scores.
+//Ascr is the acoustic score and Lscr the language model score, already scaled by -lw and including the -wip penalty. Lback is the n-gram order used for the LM score, lower than the model order when it backed off.
+type Segment struct {
+	Word       string `json:"word"`
+	StartFrame int    `json:"start_frame"`
+	EndFrame   int    `json:"end_frame"`
+	Ascr       int64  `json:"ascr"`
+	Lscr       int64  `json:"lscr"`
+	Lback      int    `json:"lback"`
+	Prob       int64  `json:"prob"`
+}
+
+//WordResult is a recognized word with its start and end time in seconds and its posterior probability between 0 and 1. The posterior is only computed if bestpath search is enabled, otherwise it is 1. Ascr and Lscr are the log domain acoustic and language model scores, as in Segment.
+type WordResult struct {
+	Word  string  `json:"word"`
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	Prob  float64 `json:"prob"`
+	Ascr  int64   `json:"ascr"`
+	Lscr  int64   `json:"lscr"`
+}
+
+//PartialHyp is a hypothesis of an utterance in progress. Its first Stable words were the same in the previous call to GetPartialHyp, so they are unlikely to change, while the following ones may still be revised as more audio is decoded.
+type PartialHyp struct {
+	Result
+	Stable int `json:"stable"`
+}
+
+//StableText gets the stable words of the hypothesis.
+func (h PartialHyp) StableText() string {
+	return strings.Join(strings.Fields(h.Text)[:h.Stable], " ")
+}
+
+//UnstableText gets the words of the hypothesis that may still change.
+func (h PartialHyp) UnstableText() string {
+	return strings.Join(strings.Fields(h.Text)[h.Stable:], " ")
+}
+
+//Decoder is the part of the API of *pocketsphinx.PocketSphinx used to decode audio and manage searches, see its methods for their documentation.
+type Decoder interface {
+	StartUtt() error
+	EndUtt() error
+	ProcessRaw(raw []int16, noSearch, fullUtt bool) (int, error)
+	ProcessRawBytes(data []byte, noSearch, fullUtt bool) (int, error)
+	ProcessUtt(raw []int16, numNbest int) ([]Result, error)
+	DecodeUtterance(raw []int16) (Result, error)
+	GetHyp() (Result, error)
+	GetPartialHyp() (PartialHyp, error)
+	GetNbest(numNbest int) []Result
+	Segments() ([]Segment, error)
+	IsInSpeech() bool
+	NumFrames() int
+
+	SetSearch(name string) error
+	GetSearch() string
+	HasSearch(name string) bool
+	Searches() []string
+	UnsetSearch(name string) error
+	SetKeyphrase(name string, keyphrase string) error
+	SetKwsFile(name, path string) error
+	SetJSGFFile(name, path string) error
+	ParseJSGF(name string, grammar string) error
+	SetLMFile(name, path string) error
+
+	AddWord(word, pronunciation string, update bool) error
+	LookupWord(word string) (pronunciation string, ok bool)
+
+	Close() error
+}
@@ -0,0 +1,347 @@
+package decoder
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//samplesPerFrame is the number of samples of a frame of FakeDecoder, 10ms at 16kHz as with the default decoder configuration.
+const samplesPerFrame = 160
+
+//FakeDecoder is a Decoder returning scripted results, for testing code using Decoder without the C library or models. Its exported fields can be set before use, and read back after it to check how it was used. It is safe for concurrent use.
+type FakeDecoder struct {
+	//Results are the hypotheses of the utterances decoded, in order: the first utterance gets Results[0], and so on. Once they are exhausted, utterances have no hypothesis and ErrNoHypothesis is returned.
+	Results []Result
+	//Nbest are the hypotheses returned by GetNbest after the best one, by utterance like Results.
+	Nbest [][]Result
+	//Err, if set, is returned by every method returning an error, e.g. to test failure handling.
+	Err error
+	//Dict maps the words of the dictionary to their pronunciation, for LookupWord and AddWord.
+	Dict map[string]string
+
+	//Audio is the audio passed for each utterance, appended by StartUtt.
+	Audio [][]int16
+	//Closed is set by Close.
+	Closed bool
+
+	mu       sync.Mutex
+	utt      int
+	inUtt    bool
+	frames   int
+	search   string
+	searches map[string]bool
+	partial  []string
+}
+
+var _ Decoder = (*FakeDecoder)(nil)
+
+//check fails if d has been closed or Err is set.
+func (d *FakeDecoder) check() error {
+	if d.Closed {
+		return ErrClosed
+	}
+	return d.Err
+}
+
+//result gets the scripted hypothesis of the current or last utterance.
+func (d *FakeDecoder) result() (Result, bool) {
+	i := d.utt - 1
+	if i < 0 || i >= len(d.Results) {
+		return Result{}, false
+	}
+	return d.Results[i], true
+}
+
+//StartUtt starts the next utterance.
+func (d *FakeDecoder) StartUtt() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.check(); err != nil {
+		return err
+	}
+	if d.inUtt {
+		return fmt.Errorf("start_utt error:utterance already started")
+	}
+	d.inUtt = true
+	d.utt++
+	d.frames = 0
+	d.partial = d.partial[:0]
+	d.Audio = append(d.Audio, nil)
+	return nil
+}
+
+//EndUtt ends the utterance.
+func (d *FakeDecoder) EndUtt() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.check(); err != nil {
+		return err
+	}
+	if !d.inUtt {
+		return fmt.Errorf("end_utt error:no utterance started")
+	}
+	d.inUtt = false
+	return nil
+}
+
+//ProcessRaw records raw as audio of the utterance, returning its number of 10ms frames.
+func (d *FakeDecoder) ProcessRaw(raw []int16, noSearch, fullUtt bool) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.check(); err != nil {
+		return 0, err
+	}
+	if len(raw) == 0 {
+		return 0, ErrEmptyInput
+	}
+	if !d.inUtt {
+		return 0, fmt.Errorf("process_raw error:no utterance started")
+	}
+	last := len(d.Audio) - 1
+	d.Audio[last] = append(d.Audio[last], raw...)
+	n := len(raw) / samplesPerFrame
+	d.frames += n
+	return n, nil
+}
+
+//ProcessRawBytes is like ProcessRaw, with the samples in little endian byte order.
+func (d *FakeDecoder) ProcessRawBytes(data []byte, noSearch, fullUtt bool) (int, error) {
+	if len(data)%2 != 0 {
+		return 0, fmt.Errorf("process_raw error:odd number of bytes %d", len(data))
+	}
+	raw := make([]int16, len(data)/2)
+	for i := range raw {
+		raw[i] = int16(data[2*i]) | int16(data[2*i+1])<<8
+	}
+	return d.ProcessRaw(raw, noSearch, fullUtt)
+}
+
+//ProcessUtt decodes raw as a whole utterance, returning its scripted hypothesis followed by up to numNbest-1 of its n-best ones.
+func (d *FakeDecoder) ProcessUtt(raw []int16, numNbest int) ([]Result, error) {
+	ret := make([]Result, 0, numNbest)
+	if err := d.StartUtt(); err != nil {
+		return ret, err
+	}
+	if _, err := d.ProcessRaw(raw, false, true); err != nil {
+		return ret, err
+	}
+	if err := d.EndUtt(); err != nil {
+		return ret, err
+	}
+	r, err := d.GetHyp()
+	if err != nil {
+		return ret, err
+	}
+	ret = append(ret, r)
+	return append(ret, d.GetNbest(numNbest-1)...), nil
+}
+
+//DecodeUtterance decodes raw as a whole utterance, returning its scripted hypothesis.
+func (d *FakeDecoder) DecodeUtterance(raw []int16) (Result, error) {
+	ret, err := d.ProcessUtt(raw, 1)
+	if err != nil {
+		return Result{}, err
+	}
+	return ret[0], nil
+}
+
+//GetHyp gets the scripted hypothesis of the current or last utterance.
+func (d *FakeDecoder) GetHyp() (Result, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.check(); err != nil {
+		return Result{}, err
+	}
+	r, ok := d.result()
+	if !ok {
+		return Result{}, ErrNoHypothesis
+	}
+	return r, nil
+}
+
+//GetPartialHyp gets the scripted hypothesis of the current utterance, with its words stable since the previous call counted as the real decoder does.
+func (d *FakeDecoder) GetPartialHyp() (PartialHyp, error) {
+	hyp, err := d.GetHyp()
+	if err != nil {
+		return PartialHyp{}, err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	words := strings.Fields(hyp.Text)
+	stable := 0
+	for stable < len(words) && stable < len(d.partial) && words[stable] == d.partial[stable] {
+		stable++
+	}
+	d.partial = append(d.partial[:0], words...)
+	return PartialHyp{Result: hyp, Stable: stable}, nil
+}
+
+//GetNbest gets up to numNbest of the scripted n-best hypotheses of the current or last utterance.
+func (d *FakeDecoder) GetNbest(numNbest int) []Result {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	i := d.utt - 1
+	if d.check() != nil || numNbest <= 0 || i < 0 || i >= len(d.Nbest) {
+		return nil
+	}
+	nbest := d.Nbest[i]
+	if len(nbest) > numNbest {
+		nbest = nbest[:numNbest]
+	}
+	return append([]Result(nil), nbest...)
+}
+
+//Segments gets the segments of the scripted hypothesis.
+func (d *FakeDecoder) Segments() ([]Segment, error) {
+	r, err := d.GetHyp()
+	return r.Segments, err
+}
+
+//IsInSpeech reports whether an utterance is in progress and its scripted hypothesis has words.
+func (d *FakeDecoder) IsInSpeech() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	r, ok := d.result()
+	return d.inUtt && ok && r.Text != ""
+}
+
+//NumFrames gets the number of 10ms frames of audio of the current or last utterance.
+func (d *FakeDecoder) NumFrames() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.frames
+}
+
+//SetSearch activates the search named name.
+func (d *FakeDecoder) SetSearch(name string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.check(); err != nil {
+		return err
+	}
+	if !d.searches[name] {
+		return fmt.Errorf("set_search %s error:%w", name, ErrUnknownSearch)
+	}
+	d.search = name
+	return nil
+}
+
+//GetSearch gets the name of the active search.
+func (d *FakeDecoder) GetSearch() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.search
+}
+
+//HasSearch reports whether a search named name was added.
+func (d *FakeDecoder) HasSearch(name string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.searches[name]
+}
+
+//Searches gets the names of the searches added, in sorted order.
+func (d *FakeDecoder) Searches() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	ret := make([]string, 0, len(d.searches))
+	for name := range d.searches {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+//UnsetSearch removes the search named name, which must not be active.
+func (d *FakeDecoder) UnsetSearch(name string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.check(); err != nil {
+		return err
+	}
+	if !d.searches[name] {
+		return fmt.Errorf("unset_search %s error:%w", name, ErrUnknownSearch)
+	}
+	if name == d.search {
+		return fmt.Errorf("unset_search %s error:search is active", name)
+	}
+	delete(d.searches, name)
+	return nil
+}
+
+//addSearch records a search named name, activating it if it is the first one, like the decoder's initial search.
+func (d *FakeDecoder) addSearch(name string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.check(); err != nil {
+		return err
+	}
+	if d.searches == nil {
+		d.searches = make(map[string]bool)
+	}
+	d.searches[name] = true
+	if d.search == "" {
+		d.search = name
+	}
+	return nil
+}
+
+//SetKeyphrase adds a keyphrase search named name. The keyphrase isn't used.
+func (d *FakeDecoder) SetKeyphrase(name string, keyphrase string) error {
+	return d.addSearch(name)
+}
+
+//SetKwsFile adds a keyword search named name. The file isn't read.
+func (d *FakeDecoder) SetKwsFile(name, path string) error {
+	return d.addSearch(name)
+}
+
+//SetJSGFFile adds a grammar search named name. The file isn't read.
+func (d *FakeDecoder) SetJSGFFile(name, path string) error {
+	return d.addSearch(name)
+}
+
+//ParseJSGF adds a grammar search named name. The grammar isn't parsed.
+func (d *FakeDecoder) ParseJSGF(name string, grammar string) error {
+	return d.addSearch(name)
+}
+
+//SetLMFile adds a language model search named name. The file isn't read.
+func (d *FakeDecoder) SetLMFile(name, path string) error {
+	return d.addSearch(name)
+}
+
+//AddWord adds word to Dict, failing if it is already there as the decoder does.
+func (d *FakeDecoder) AddWord(word, pronunciation string, update bool) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.check(); err != nil {
+		return err
+	}
+	if _, ok := d.Dict[word]; ok {
+		return fmt.Errorf("add_word error:%s already in dictionary", word)
+	}
+	if d.Dict == nil {
+		d.Dict = make(map[string]string)
+	}
+	d.Dict[word] = pronunciation
+	return nil
+}
+
+//LookupWord gets the pronunciation of word from Dict.
+func (d *FakeDecoder) LookupWord(word string) (pronunciation string, ok bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	pronunciation, ok = d.Dict[word]
+	return pronunciation, ok
+}
+
+//Close sets Closed, after which methods return ErrClosed.
+func (d *FakeDecoder) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.Closed = true
+	return nil
+}
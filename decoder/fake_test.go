@@ -0,0 +1,236 @@
+package decoder
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestFakeDecoderProcessUtt(t *testing.T) {
+	tests := []struct {
+		name     string
+		d        *FakeDecoder
+		numNbest int
+		want     []string
+		err      error
+	}{
+		{
+			name: "best",
+			d:    &FakeDecoder{Results: []Result{{Text: "turn on the lights"}}},
+			want: []string{"turn on the lights"},
+		},
+		{
+			name:     "nbest",
+			d:        &FakeDecoder{Results: []Result{{Text: "turn on the lights"}}, Nbest: [][]Result{{{Text: "turn on the light"}, {Text: "turn off the lights"}}}},
+			numNbest: 2,
+			want:     []string{"turn on the lights", "turn on the light"},
+		},
+		{
+			name:     "fewer nbest than asked",
+			d:        &FakeDecoder{Results: []Result{{Text: "stop"}}, Nbest: [][]Result{{{Text: "top"}}}},
+			numNbest: 5,
+			want:     []string{"stop", "top"},
+		},
+		{
+			name: "results exhausted",
+			d:    &FakeDecoder{},
+			err:  ErrNoHypothesis,
+		},
+		{
+			name: "scripted error",
+			d:    &FakeDecoder{Results: []Result{{Text: "stop"}}, Err: ErrClosed},
+			err:  ErrClosed,
+		},
+		{
+			name: "closed",
+			d:    &FakeDecoder{Results: []Result{{Text: "stop"}}, Closed: true},
+			err:  ErrClosed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := tt.d.ProcessUtt(make([]int16, 1600), tt.numNbest)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("ProcessUtt error %v, want %v", err, tt.err)
+			}
+			var got []string
+			for _, r := range results {
+				got = append(got, r.Text)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ProcessUtt got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFakeDecoderUtterances(t *testing.T) {
+	d := &FakeDecoder{Results: []Result{{Text: "one"}, {Text: "two"}}}
+	for i, want := range []string{"one", "two"} {
+		r, err := d.DecodeUtterance(make([]int16, 320*(i+1)))
+		if err != nil {
+			t.Fatalf("utterance %d error %v", i, err)
+		}
+		if r.Text != want {
+			t.Errorf("utterance %d got %q, want %q", i, r.Text, want)
+		}
+		if frames := d.NumFrames(); frames != 2*(i+1) {
+			t.Errorf("utterance %d got %d frames, want %d", i, frames, 2*(i+1))
+		}
+	}
+	if _, err := d.DecodeUtterance(make([]int16, 160)); !errors.Is(err, ErrNoHypothesis) {
+		t.Errorf("third utterance error %v, want %v", err, ErrNoHypothesis)
+	}
+	if len(d.Audio) != 3 || len(d.Audio[1]) != 640 {
+		t.Errorf("recorded audio of %d utterances", len(d.Audio))
+	}
+}
+
+func TestFakeDecoderProcessRaw(t *testing.T) {
+	tests := []struct {
+		name    string
+		started bool
+		raw     []int16
+		frames  int
+		wantErr bool
+	}{
+		{name: "frames", started: true, raw: make([]int16, 480), frames: 3},
+		{name: "partial frame", started: true, raw: make([]int16, 200), frames: 1},
+		{name: "empty", started: true, wantErr: true},
+		{name: "not started", raw: make([]int16, 160), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &FakeDecoder{}
+			if tt.started {
+				if err := d.StartUtt(); err != nil {
+					t.Fatal(err)
+				}
+			}
+			frames, err := d.ProcessRaw(tt.raw, false, false)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ProcessRaw error %v, want error %v", err, tt.wantErr)
+			}
+			if frames != tt.frames {
+				t.Errorf("ProcessRaw got %d frames, want %d", frames, tt.frames)
+			}
+		})
+	}
+}
+
+func TestFakeDecoderProcessRawBytes(t *testing.T) {
+	d := &FakeDecoder{}
+	if err := d.StartUtt(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.ProcessRawBytes([]byte{1, 0, 0xff, 0xff, 0, 0x80}, false, false); err != nil {
+		t.Fatal(err)
+	}
+	if want := []int16{1, -1, -32768}; !reflect.DeepEqual(d.Audio[0], want) {
+		t.Errorf("got samples %v, want %v", d.Audio[0], want)
+	}
+	if _, err := d.ProcessRawBytes([]byte{1, 2, 3}, false, false); err == nil {
+		t.Error("odd number of bytes accepted")
+	}
+}
+
+func TestFakeDecoderPartialHyp(t *testing.T) {
+	d := &FakeDecoder{Results: []Result{{Text: "turn on the lights"}}}
+	if err := d.StartUtt(); err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []int{0, 4} {
+		hyp, err := d.GetPartialHyp()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hyp.Stable != want {
+			t.Errorf("call %d got %d stable words, want %d", i, hyp.Stable, want)
+		}
+	}
+}
+
+func TestPartialHypText(t *testing.T) {
+	tests := []struct {
+		hyp              PartialHyp
+		stable, unstable string
+	}{
+		{PartialHyp{Result: Result{Text: "turn on the lights"}, Stable: 2}, "turn on", "the lights"},
+		{PartialHyp{Result: Result{Text: "turn on"}, Stable: 0}, "", "turn on"},
+		{PartialHyp{Result: Result{Text: "turn on"}, Stable: 2}, "turn on", ""},
+		{PartialHyp{}, "", ""},
+	}
+	for _, tt := range tests {
+		if got := tt.hyp.StableText(); got != tt.stable {
+			t.Errorf("%q StableText got %q, want %q", tt.hyp.Text, got, tt.stable)
+		}
+		if got := tt.hyp.UnstableText(); got != tt.unstable {
+			t.Errorf("%q UnstableText got %q, want %q", tt.hyp.Text, got, tt.unstable)
+		}
+	}
+}
+
+func TestFakeDecoderSearches(t *testing.T) {
+	d := &FakeDecoder{}
+	if err := d.SetKeyphrase("wake", "oh mighty computer"); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.ParseJSGF("commands", "#JSGF V1.0;"); err != nil {
+		t.Fatal(err)
+	}
+	if got := d.GetSearch(); got != "wake" {
+		t.Errorf("first search added isn't active, got %q", got)
+	}
+	if got, want := d.Searches(), []string{"commands", "wake"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Searches got %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		name string
+		op   func() error
+		err  error
+	}{
+		{name: "set unknown", op: func() error { return d.SetSearch("missing") }, err: ErrUnknownSearch},
+		{name: "unset unknown", op: func() error { return d.UnsetSearch("missing") }, err: ErrUnknownSearch},
+		{name: "set", op: func() error { return d.SetSearch("commands") }},
+		{name: "unset active", op: func() error { return d.UnsetSearch("commands") }, err: errors.New("")},
+		{name: "unset", op: func() error { return d.UnsetSearch("wake") }},
+	}
+	for _, tt := range tests {
+		err := tt.op()
+		switch {
+		case tt.err == nil && err != nil:
+			t.Errorf("%s error %v", tt.name, err)
+		case tt.err != nil && err == nil:
+			t.Errorf("%s succeeded", tt.name)
+		case tt.err == ErrUnknownSearch && !errors.Is(err, ErrUnknownSearch):
+			t.Errorf("%s error %v, want %v", tt.name, err, ErrUnknownSearch)
+		}
+	}
+	if d.HasSearch("wake") || !d.HasSearch("commands") {
+		t.Errorf("searches after unset %v", d.Searches())
+	}
+}
+
+func TestFakeDecoderWords(t *testing.T) {
+	d := &FakeDecoder{Dict: map[string]string{"hello": "HH AH L OW"}}
+	tests := []struct {
+		word, pron string
+		wantErr    bool
+	}{
+		{word: "world", pron: "W ER L D"},
+		{word: "hello", pron: "HH EH L OW", wantErr: true},
+		{word: "hello(2)", pron: "HH EH L OW"},
+	}
+	for _, tt := range tests {
+		if err := d.AddWord(tt.word, tt.pron, true); (err != nil) != tt.wantErr {
+			t.Errorf("AddWord %s error %v, want error %v", tt.word, err, tt.wantErr)
+		}
+	}
+	if pron, ok := d.LookupWord("hello"); !ok || pron != "HH AH L OW" {
+		t.Errorf("LookupWord hello got %q %v", pron, ok)
+	}
+	if _, ok := d.LookupWord("missing"); ok {
+		t.Error("LookupWord found a missing word")
+	}
+}
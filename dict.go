@@ -0,0 +1,84 @@
+package pocketsphinx
+
+/*
+#include <pocketsphinx.h>
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"strings"
+	"unsafe"
+)
+
+//AddWord adds word to the dictionary with pronunciation, a space separated list of phones of the acoustic model, e.g. "HH AH L OW". If update is true, the active search is rebuilt to include the word, which should be done for the last of several words added at once. A word already in the dictionary is an error; alternate pronunciations are added as distinct words numbered from 2, e.g. "read(2)".
+func (p *PocketSphinx) AddWord(word, pronunciation string, update bool) error {
+	if err := p.acquire(); err != nil {
+		return err
+	}
+	defer p.release()
+	cword := C.CString(word)
+	cphones := C.CString(strings.Join(strings.Fields(pronunciation), " "))
+	defer C.free(unsafe.Pointer(cword))
+	defer C.free(unsafe.Pointer(cphones))
+	var ret C.int
+	reason := captureErrors(func() {
+		ret = C.ps_add_word(p.ps, cword, cphones, C.int(bool2int(update)))
+	})
+	if ret < 0 {
+		if reason == "" {
+			return fmt.Errorf("add_word %s error", word)
+		}
+		return fmt.Errorf("add_word %s error:%s", word, reason)
+	}
+	return nil
+}
+
+//LookupWord gets the pronunciation of word in the dictionary, as a space separated list of phones. ok is false if the word is missing.
+func (p *PocketSphinx) LookupWord(word string) (pronunciation string, ok bool) {
+	if p.acquire() != nil {
+		return "", false
+	}
+	defer p.release()
+	cword := C.CString(word)
+	defer C.free(unsafe.Pointer(cword))
+	cphones := C.ps_lookup_word(p.ps, cword)
+	if cphones == nil {
+		return "", false
+	}
+	defer C.free(unsafe.Pointer(cphones))
+	return C.GoString(cphones), true
+}
+
+//MissingWords gets the words of text missing from the dictionary, e.g. to check a keyphrase or the phrases of a grammar before adding a search for them.
+func (p *PocketSphinx) MissingWords(text string) []string {
+	var missing []string
+	for _, word := range strings.Fields(text) {
+		if _, ok := p.LookupWord(word); !ok {
+			missing = append(missing, word)
+		}
+	}
+	return missing
+}
+
+//SaveDict writes the dictionary, including words added with AddWord, to path in the sphinx dictionary format, so it can be loaded with -dict on the next start.
+func (p *PocketSphinx) SaveDict(path string) error {
+	if err := p.acquire(); err != nil {
+		return err
+	}
+	defer p.release()
+	cpath := C.CString(path)
+	defer C.free(unsafe.Pointer(cpath))
+	var ret C.int
+	reason := captureErrors(func() {
+		ret = C.ps_save_dict(p.ps, cpath, nil)
+	})
+	if ret != 0 {
+		if reason == "" {
+			return fmt.Errorf("save_dict error:%s", path)
+		}
+		return fmt.Errorf("save_dict error:%s:%s", path, reason)
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package pocketsphinx
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+//Dict is a pronunciation dictionary in memory, read from and written to files in the CMUdict format used by -dict: one word per line followed by its phones, with alternate pronunciations marked as word(2), word(3) and so on. It doesn't need a decoder, and can be added to one with AddDict.
+type Dict struct {
+	prons map[string][]string
+}
+
+//DictConflict is a word with different pronunciations in two merged dictionaries.
+type DictConflict struct {
+	Word     string
+	Existing []string
+	Added    []string
+}
+
+//NewDict creates an empty Dict.
+func NewDict() *Dict {
+	return &Dict{prons: make(map[string][]string)}
+}
+
+//LoadDict reads the dictionary file at path.
+func LoadDict(path string) (*Dict, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	d, err := ReadDict(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%v", path, err)
+	}
+	return d, nil
+}
+
+//ReadDict reads a dictionary from r. Lines starting with ;;; or # are comments, as is the rest of a line after #. Repeated pronunciations of a word are read once.
+func ReadDict(r io.Reader) (*Dict, error) {
+	d := NewDict()
+	sc := bufio.NewScanner(r)
+	line := 0
+	for sc.Scan() {
+		line++
+		text := sc.Text()
+		if strings.HasPrefix(text, ";;;") {
+			continue
+		}
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) == 1 {
+			return nil, fmt.Errorf("line %d:no pronunciation for %s", line, fields[0])
+		}
+		d.Add(baseWord(fields[0]), strings.Join(fields[1:], " "))
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+//baseWord strips the alternate pronunciation marker from word.
+func baseWord(word string) string {
+	if i := strings.IndexByte(word, '('); i > 0 && strings.HasSuffix(word, ")") {
+		return word[:i]
+	}
+	return word
+}
+
+//Len gets the number of words in the dictionary.
+func (d *Dict) Len() int {
+	return len(d.prons)
+}
+
+//Words gets the words of the dictionary in sorted order.
+func (d *Dict) Words() []string {
+	words := make([]string, 0, len(d.prons))
+	for word := range d.prons {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+	return words
+}
+
+//Lookup gets the pronunciations of word, the first being the main one.
+func (d *Dict) Lookup(word string) []string {
+	return append([]string(nil), d.prons[word]...)
+}
+
+//Add adds pronunciation to word, as an alternate one if the word already has one. It returns false if the word already had this pronunciation.
+func (d *Dict) Add(word, pronunciation string) bool {
+	pronunciation = strings.Join(strings.Fields(pronunciation), " ")
+	for _, pron := range d.prons[word] {
+		if pron == pronunciation {
+			return false
+		}
+	}
+	d.prons[word] = append(d.prons[word], pronunciation)
+	return true
+}
+
+//Remove removes word and all of its pronunciations. It returns false if the word was missing.
+func (d *Dict) Remove(word string) bool {
+	_, ok := d.prons[word]
+	delete(d.prons, word)
+	return ok
+}
+
+//Merge adds the words of o to d. Pronunciations of words already in d that differ from the ones in d are added as alternates, and the words are returned as conflicts.
+func (d *Dict) Merge(o *Dict) []DictConflict {
+	var conflicts []DictConflict
+	for _, word := range o.Words() {
+		existing := d.Lookup(word)
+		var added []string
+		for _, pron := range o.prons[word] {
+			if d.Add(word, pron) && len(existing) > 0 {
+				added = append(added, pron)
+			}
+		}
+		if len(added) > 0 {
+			conflicts = append(conflicts, DictConflict{Word: word, Existing: existing, Added: added})
+		}
+	}
+	return conflicts
+}
+
+//Homophones gets the pronunciations shared by several words, with the sorted words sharing them. Homophones can't be told apart by the decoder other than by the language model or grammar.
+func (d *Dict) Homophones() map[string][]string {
+	words := make(map[string][]string)
+	for _, word := range d.Words() {
+		for _, pron := range d.prons[word] {
+			words[pron] = append(words[pron], word)
+		}
+	}
+	for pron, ws := range words {
+		if len(ws) < 2 {
+			delete(words, pron)
+		}
+	}
+	return words
+}
+
+//Write writes the dictionary to w in sorted order.
+func (d *Dict) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, word := range d.Words() {
+		for i, pron := range d.prons[word] {
+			if i == 0 {
+				fmt.Fprintf(bw, "%s %s\n", word, pron)
+				continue
+			}
+			fmt.Fprintf(bw, "%s(%d) %s\n", word, i+1, pron)
+		}
+	}
+	return bw.Flush()
+}
+
+//Save writes the dictionary to the file at path.
+func (d *Dict) Save(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := d.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+//AddDict adds the words of d missing from the decoder's dictionary with AddWord, their alternate pronunciations as word(2), word(3) and so on, and updates the active search once at the end. Words already in the decoder's dictionary are left as they are.
+func (p *PocketSphinx) AddDict(d *Dict) error {
+	var pending [][2]string
+	for _, word := range d.Words() {
+		if _, ok := p.LookupWord(word); ok {
+			continue
+		}
+		for i, pron := range d.prons[word] {
+			name := word
+			if i > 0 {
+				name = fmt.Sprintf("%s(%d)", word, i+1)
+			}
+			pending = append(pending, [2]string{name, pron})
+		}
+	}
+	for i, wp := range pending {
+		if err := p.AddWord(wp[0], wp[1], i == len(pending)-1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package pocketsphinx
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadDict(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		prons map[string][]string
+		err   string
+	}{
+		{
+			name:  "words",
+			text:  "hello HH AH L OW\nworld W ER L D\n",
+			prons: map[string][]string{"hello": {"HH AH L OW"}, "world": {"W ER L D"}},
+		},
+		{
+			name:  "alternates",
+			text:  "read R IY D\nread(2) R EH D\n",
+			prons: map[string][]string{"read": {"R IY D", "R EH D"}},
+		},
+		{
+			name:  "comments and blank lines",
+			text:  ";;; CMUdict\n# header\n\nhello\tHH  AH L OW # greeting\n",
+			prons: map[string][]string{"hello": {"HH AH L OW"}},
+		},
+		{
+			name:  "repeated pronunciation",
+			text:  "the DH AH\nthe(2) DH AH\n",
+			prons: map[string][]string{"the": {"DH AH"}},
+		},
+		{
+			name: "no pronunciation",
+			text: "hello HH AH L OW\nworld\n",
+			err:  "line 2:no pronunciation for world",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := ReadDict(strings.NewReader(tt.text))
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("ReadDict error %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(d.prons, tt.prons) {
+				t.Errorf("got %v, want %v", d.prons, tt.prons)
+			}
+		})
+	}
+}
+
+func TestDictWrite(t *testing.T) {
+	text := "hello HH AH L OW\nread R IY D\nread(2) R EH D\nworld W ER L D\n"
+	d, err := ReadDict(strings.NewReader("world W ER L D\nread R IY D\nhello HH AH L OW\nread(2) R EH D\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := d.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != text {
+		t.Errorf("Write got\n%s\nwant\n%s", buf.String(), text)
+	}
+	again, err := ReadDict(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again.prons, d.prons) {
+		t.Errorf("round trip got %v, want %v", again.prons, d.prons)
+	}
+}
+
+func TestDictEdit(t *testing.T) {
+	d := NewDict()
+	tests := []struct {
+		name string
+		op   func() bool
+		want bool
+	}{
+		{"add", func() bool { return d.Add("read", "R IY D") }, true},
+		{"add alternate", func() bool { return d.Add("read", "R  EH D") }, true},
+		{"add existing", func() bool { return d.Add("read", "R EH D") }, false},
+		{"add homophone", func() bool { return d.Add("red", "R EH D") }, true},
+		{"remove missing", func() bool { return d.Remove("blue") }, false},
+		{"add removed", func() bool { return d.Add("blue", "B L UW") }, true},
+		{"remove", func() bool { return d.Remove("blue") }, true},
+	}
+	for _, tt := range tests {
+		if got := tt.op(); got != tt.want {
+			t.Errorf("%s got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if got, want := d.Words(), []string{"read", "red"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Words got %v, want %v", got, want)
+	}
+	if got, want := d.Lookup("read"), []string{"R IY D", "R EH D"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Lookup got %v, want %v", got, want)
+	}
+	if got, want := d.Homophones(), map[string][]string{"R EH D": {"read", "red"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Homophones got %v, want %v", got, want)
+	}
+}
+
+func TestDictMerge(t *testing.T) {
+	d := NewDict()
+	d.Add("read", "R IY D")
+	d.Add("hello", "HH AH L OW")
+	o := NewDict()
+	o.Add("read", "R EH D")
+	o.Add("hello", "HH AH L OW")
+	o.Add("world", "W ER L D")
+
+	conflicts := d.Merge(o)
+	want := []DictConflict{{Word: "read", Existing: []string{"R IY D"}, Added: []string{"R EH D"}}}
+	if !reflect.DeepEqual(conflicts, want) {
+		t.Errorf("Merge got conflicts %+v, want %+v", conflicts, want)
+	}
+	if d.Len() != 3 || len(d.Lookup("read")) != 2 {
+		t.Errorf("merged %v", d.prons)
+	}
+}
@@ -0,0 +1,205 @@
+package pocketsphinx
+
+import (
+	"math"
+	"time"
+)
+
+//EndpointerOptions configures an Endpointer. Zero fields take the defaults noted on each.
+type EndpointerOptions struct {
+	//SampleRate is the sampling rate of the audio. It defaults to 16000.
+	SampleRate int
+	//FrameLength is the duration of the frames passed to Process. It defaults to 30ms.
+	FrameLength time.Duration
+	//Window is the duration over which speech is detected, and by which output is delayed. It defaults to 300ms.
+	Window time.Duration
+	//Ratio is the fraction of frames in Window that must be speech to start speech, or silence to end it. It defaults to 0.9.
+	Ratio float64
+	//Threshold is the level in dBFS above which a frame counts as speech, for the Go implementation. It defaults to -40.
+	Threshold float64
+	//Mode is the aggressiveness of the voice activity detector of pocketsphinx 5, used in place of Threshold by its endpointer. It defaults to VADLoose.
+	Mode VADMode
+}
+
+//VADMode is the aggressiveness of the voice activity detector of pocketsphinx 5, from VADLoose, which classifies the most frames as speech, to VADStrict.
+type VADMode int
+
+const (
+	VADLoose VADMode = iota
+	VADMediumLoose
+	VADMediumStrict
+	VADStrict
+)
+
+//Endpointer detects the start and end of speech in continuous audio. Audio is passed one frame at a time and the frames of speech are returned, delayed by the window so that the speech start, including the frames leading up to it, is never lost.
+//
+//With the pocketsphinx5 build tag, it is backed by ps_endpointer_t, the endpointer of the library, whose voice activity detector is configured by Mode. The legacy API has no endpointer, so it falls back to an implementation in Go modeled on it, detecting speech from its energy with Threshold. It also falls back when pocketsphinx 5 rejects the options, e.g. a sampling rate its voice activity detector doesn't support. Capabilities reports whether the library has an endpointer.
+type Endpointer struct {
+	native *nativeEndpointer
+
+	frameSize int
+	frameLen  float64
+	threshold float64
+	needed    int
+
+	queue    []epFrame
+	nSpeech  int
+	popped   int
+	inSpeech bool
+	start    float64
+	end      float64
+	out      []int16
+}
+
+type epFrame struct {
+	samples []int16
+	speech  bool
+}
+
+//NewEndpointer creates Endpointer configured by opts, using the endpointer of the library where it exists.
+func NewEndpointer(opts EndpointerOptions) *Endpointer {
+	if opts.SampleRate <= 0 {
+		opts.SampleRate = 16000
+	}
+	if opts.FrameLength <= 0 {
+		opts.FrameLength = 30 * time.Millisecond
+	}
+	if opts.Window <= 0 {
+		opts.Window = 300 * time.Millisecond
+	}
+	if opts.Ratio <= 0 {
+		opts.Ratio = 0.9
+	}
+	if opts.Threshold == 0 {
+		opts.Threshold = -40
+	}
+	if native := newNativeEndpointer(opts); native != nil {
+		return &Endpointer{native: native}
+	}
+	frameSize := int(float64(opts.SampleRate) * opts.FrameLength.Seconds())
+	window := int(opts.Window / opts.FrameLength)
+	if window < 1 {
+		window = 1
+	}
+	return &Endpointer{
+		frameSize: frameSize,
+		frameLen:  float64(frameSize) / float64(opts.SampleRate),
+		threshold: opts.Threshold,
+		needed:    int(math.Ceil(opts.Ratio * float64(window))),
+		queue:     make([]epFrame, 0, window),
+	}
+}
+
+//FrameSize gets the number of samples Process expects.
+func (e *Endpointer) FrameSize() int {
+	if e.native != nil {
+		return e.native.frameSize()
+	}
+	return e.frameSize
+}
+
+//InSpeech reports whether the frames being returned are speech.
+func (e *Endpointer) InSpeech() bool {
+	if e.native != nil {
+		return e.native.inSpeech()
+	}
+	return e.inSpeech
+}
+
+//SpeechStart gets the time in seconds from the start of the audio at which the current or last speech started.
+func (e *Endpointer) SpeechStart() float64 {
+	if e.native != nil {
+		return e.native.speechStart()
+	}
+	return e.start
+}
+
+//SpeechEnd gets the time in seconds from the start of the audio at which the last speech ended.
+func (e *Endpointer) SpeechEnd() float64 {
+	if e.native != nil {
+		return e.native.speechEnd()
+	}
+	return e.end
+}
+
+//Process takes a frame of FrameSize samples and returns a frame of speech, or nil if there's none. The returned slice is only valid until the next call.
+func (e *Endpointer) Process(frame []int16) []int16 {
+	if e.native != nil {
+		return e.native.process(frame)
+	}
+	e.push(frame)
+	if len(e.queue) < cap(e.queue) {
+		return nil
+	}
+
+	if !e.inSpeech && e.nSpeech >= e.needed {
+		e.inSpeech = true
+		e.start = float64(e.popped) * e.frameLen
+	} else if e.inSpeech && len(e.queue)-e.nSpeech >= e.needed {
+		e.inSpeech = false
+		e.end = float64(e.popped+1) * e.frameLen
+		return e.pop()
+	}
+	out := e.pop()
+	if !e.inSpeech {
+		return nil
+	}
+	return out
+}
+
+//EndStream takes the last, possibly short, frame of the audio and returns the remaining speech, or nil if there's none. The returned slice is only valid until the next call.
+func (e *Endpointer) EndStream(frame []int16) []int16 {
+	if e.native != nil {
+		return e.native.endStream(frame)
+	}
+	if len(frame) > 0 {
+		e.push(frame)
+	}
+	e.out = e.out[:0]
+	for _, f := range e.queue {
+		e.out = append(e.out, f.samples...)
+	}
+	n := len(e.queue)
+	e.queue = e.queue[:0]
+	e.nSpeech = 0
+	e.popped += n
+	if !e.inSpeech {
+		return nil
+	}
+	e.inSpeech = false
+	e.end = float64(e.popped) * e.frameLen
+	return e.out
+}
+
+func (e *Endpointer) push(frame []int16) {
+	f := epFrame{samples: append([]int16(nil), frame...), speech: level(frame) > e.threshold}
+	if f.speech {
+		e.nSpeech++
+	}
+	e.queue = append(e.queue, f)
+}
+
+func (e *Endpointer) pop() []int16 {
+	f := e.queue[0]
+	copy(e.queue, e.queue[1:])
+	e.queue = e.queue[:len(e.queue)-1]
+	if f.speech {
+		e.nSpeech--
+	}
+	e.popped++
+	e.out = append(e.out[:0], f.samples...)
+	return e.out
+}
+
+//level gets the RMS level of frame in dBFS.
+func level(frame []int16) float64 {
+	if len(frame) == 0 {
+		return math.Inf(-1)
+	}
+	var sum float64
+	for _, s := range frame {
+		sum += float64(s) * float64(s)
+	}
+	rms := math.Sqrt(sum / float64(len(frame)))
+	return 20 * math.Log10(rms/32768)
+}
@@ -0,0 +1,35 @@
+//go:build !pocketsphinx5
+
+package pocketsphinx
+
+//nativeEndpointer is unavailable with the legacy API, which has no endpointer, so Endpointer always uses its Go implementation.
+type nativeEndpointer struct{}
+
+//newNativeEndpointer returns nil.
+func newNativeEndpointer(opts EndpointerOptions) *nativeEndpointer {
+	return nil
+}
+
+func (n *nativeEndpointer) frameSize() int {
+	return 0
+}
+
+func (n *nativeEndpointer) inSpeech() bool {
+	return false
+}
+
+func (n *nativeEndpointer) speechStart() float64 {
+	return 0
+}
+
+func (n *nativeEndpointer) speechEnd() float64 {
+	return 0
+}
+
+func (n *nativeEndpointer) process(frame []int16) []int16 {
+	return nil
+}
+
+func (n *nativeEndpointer) endStream(frame []int16) []int16 {
+	return nil
+}
@@ -0,0 +1,82 @@
+//go:build pocketsphinx5
+
+package pocketsphinx
+
+/*
+#include <pocketsphinx.h>
+#include <pocketsphinx/endpointer.h>
+*/
+import "C"
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+//nativeEndpointer is the ps_endpointer_t of pocketsphinx 5, whose voice activity detector backs Endpointer.
+type nativeEndpointer struct {
+	ep   *C.ps_endpointer_t
+	size int
+	in   []int16
+	out  []int16
+}
+
+//newNativeEndpointer creates the endpointer of the library configured by opts, with its defaults applied, or returns nil if the library rejects them, e.g. for sampling rates its voice activity detector doesn't support.
+func newNativeEndpointer(opts EndpointerOptions) *nativeEndpointer {
+	ep := C.ps_endpointer_init(C.double(opts.Window.Seconds()), C.double(opts.Ratio), C.ps_vad_mode_t(opts.Mode), C.int(opts.SampleRate), C.double(opts.FrameLength.Seconds()))
+	if ep == nil {
+		return nil
+	}
+	n := &nativeEndpointer{ep: ep, size: int(C.ps_vad_frame_size(C.ps_endpointer_vad(ep)))}
+	runtime.SetFinalizer(n, func(n *nativeEndpointer) {
+		C.ps_endpointer_free(n.ep)
+	})
+	return n
+}
+
+func (n *nativeEndpointer) frameSize() int {
+	return n.size
+}
+
+func (n *nativeEndpointer) inSpeech() bool {
+	return C.ps_endpointer_in_speech(n.ep) != 0
+}
+
+func (n *nativeEndpointer) speechStart() float64 {
+	return float64(C.ps_endpointer_speech_start(n.ep))
+}
+
+func (n *nativeEndpointer) speechEnd() float64 {
+	return float64(C.ps_endpointer_speech_end(n.ep))
+}
+
+//process passes frame to the library, padded with silence if short, as it reads exactly a frame.
+func (n *nativeEndpointer) process(frame []int16) []int16 {
+	if len(frame) < n.size {
+		n.in = append(n.in[:0], frame...)
+		n.in = append(n.in, make([]int16, n.size-len(frame))...)
+		frame = n.in
+	}
+	speech := C.ps_endpointer_process(n.ep, (*C.int16)(unsafe.Pointer(&frame[0])))
+	runtime.KeepAlive(n)
+	if speech == nil {
+		return nil
+	}
+	n.out = append(n.out[:0], unsafe.Slice((*int16)(unsafe.Pointer(speech)), n.size)...)
+	return n.out
+}
+
+func (n *nativeEndpointer) endStream(frame []int16) []int16 {
+	var cframe *C.int16
+	if len(frame) > 0 {
+		cframe = (*C.int16)(unsafe.Pointer(&frame[0]))
+	}
+	var nsamp C.size_t
+	speech := C.ps_endpointer_end_stream(n.ep, cframe, C.size_t(len(frame)), &nsamp)
+	runtime.KeepAlive(n)
+	if speech == nil || nsamp == 0 {
+		return nil
+	}
+	n.out = append(n.out[:0], unsafe.Slice((*int16)(unsafe.Pointer(speech)), int(nsamp))...)
+	return n.out
+}
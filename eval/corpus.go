@@ -0,0 +1,126 @@
+package eval
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//Sample is an utterance of a reference corpus.
+type Sample struct {
+	ID string
+	//Audio is the single channel, 16-bit pcm audio of the utterance. If nil, it is read from Path when evaluated.
+	Audio []int16
+	//Path is a WAV file, or raw little endian 16-bit pcm audio if it has another extension.
+	Path string
+	//Reference is the transcript of the utterance.
+	Reference string
+}
+
+//LoadCorpus reads a corpus in the sphinxtrain layout: fileids lists the utterances, one path per line relative to audioDir and without extension, and transcription has their transcripts, one per line in the same order, each followed by the id in parentheses, e.g. "<s> hello world </s> (speaker1/utt1)". ext is the extension of the audio files, e.g. ".wav" or ".raw". The audio is read when the samples are evaluated.
+func LoadCorpus(fileids, transcription, audioDir, ext string) ([]Sample, error) {
+	ids, err := readLines(fileids)
+	if err != nil {
+		return nil, fmt.Errorf("fileids error:%v", err)
+	}
+	refs, err := readLines(transcription)
+	if err != nil {
+		return nil, fmt.Errorf("transcription error:%v", err)
+	}
+	if len(ids) != len(refs) {
+		return nil, fmt.Errorf("corpus error:%d fileids but %d transcriptions", len(ids), len(refs))
+	}
+	samples := make([]Sample, len(ids))
+	for i, id := range ids {
+		ref := refs[i]
+		if open := strings.LastIndexByte(ref, '('); open >= 0 && strings.HasSuffix(ref, ")") {
+			if uttid := ref[open+1 : len(ref)-1]; uttid != filepath.Base(id) && uttid != id {
+				return nil, fmt.Errorf("corpus error:line %d transcribes %s rather than %s", i+1, uttid, id)
+			}
+			ref = strings.TrimSpace(ref[:open])
+		}
+		samples[i] = Sample{
+			ID:        id,
+			Path:      filepath.Join(audioDir, filepath.FromSlash(id)+ext),
+			Reference: ref,
+		}
+	}
+	return samples, nil
+}
+
+func readLines(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var ret []string
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		if line := strings.TrimSpace(s.Text()); line != "" {
+			ret = append(ret, line)
+		}
+	}
+	return ret, s.Err()
+}
+
+//readAudio reads the audio of a sample, and its sampling rate if it is a WAV file, 0 otherwise.
+func readAudio(path string) ([]int16, int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	rate := 0
+	if strings.EqualFold(filepath.Ext(path), ".wav") {
+		data, rate, err = wavData(data)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%s:%v", path, err)
+		}
+	}
+	samples := make([]int16, len(data)/2)
+	binary.Read(bytes.NewReader(data), binary.LittleEndian, samples)
+	return samples, rate, nil
+}
+
+//wavData gets the samples of single channel, 16-bit pcm WAV data, and their sampling rate.
+func wavData(data []byte) ([]byte, int, error) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return nil, 0, errors.New("not a WAV file")
+	}
+	rate := 0
+	for chunk := data[12:]; len(chunk) >= 8; {
+		id, size := string(chunk[:4]), int(binary.LittleEndian.Uint32(chunk[4:8]))
+		chunk = chunk[8:]
+		if size > len(chunk) {
+			size = len(chunk)
+		}
+		switch id {
+		case "fmt ":
+			if size < 16 {
+				return nil, 0, errors.New("short fmt chunk")
+			}
+			format := binary.LittleEndian.Uint16(chunk[0:2])
+			channels := binary.LittleEndian.Uint16(chunk[2:4])
+			bits := binary.LittleEndian.Uint16(chunk[14:16])
+			if format != 1 || channels != 1 || bits != 16 {
+				return nil, 0, fmt.Errorf("unsupported format %d, %d channels, %d bits", format, channels, bits)
+			}
+			rate = int(binary.LittleEndian.Uint32(chunk[4:8]))
+		case "data":
+			if rate == 0 {
+				return nil, 0, errors.New("data before fmt chunk")
+			}
+			return chunk[:size], rate, nil
+		}
+		if size += size % 2; size > len(chunk) {
+			break
+		}
+		chunk = chunk[size:]
+	}
+	return nil, 0, errors.New("no data chunk")
+}
@@ -0,0 +1,163 @@
+package eval
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/andyleap/pocketsphinx/decoder"
+)
+
+//Evaluator decodes the samples of a corpus and scores the hypotheses against their references.
+type Evaluator struct {
+	//Decoder decodes the samples, one utterance each.
+	Decoder decoder.Decoder
+	//SampleRate is the sampling rate of the audio, to report its duration, and to check that of WAV files. It defaults to 16000.
+	SampleRate int
+	//Normalize splits references and hypotheses into words before aligning them. It defaults to Words.
+	Normalize func(text string) []string
+	//Progress, if set, is called after each sample is evaluated.
+	Progress func(done, total int, file FileResult)
+}
+
+//FileResult is the outcome of evaluating a sample. Samples that failed to decode have Err set and aren't scored.
+type FileResult struct {
+	ID         string        `json:"id"`
+	Reference  string        `json:"reference"`
+	Hypothesis string        `json:"hypothesis"`
+	Score      Score         `json:"score"`
+	Audio      time.Duration `json:"audio"`
+	Elapsed    time.Duration `json:"elapsed"`
+	Err        error         `json:"-"`
+}
+
+//Report is the outcome of evaluating a corpus. Files are in corpus order, and Total aggregates the scores of those that decoded.
+type Report struct {
+	Files  []FileResult `json:"files"`
+	Total  Score        `json:"total"`
+	Failed int          `json:"failed"`
+	//Sentences is the number of decoded samples whose hypothesis had no errors.
+	Sentences int `json:"correct_sentences"`
+	//Audio is the total duration of the audio decoded.
+	Audio time.Duration `json:"audio"`
+	//Elapsed is the time spent decoding.
+	Elapsed time.Duration `json:"elapsed"`
+}
+
+//WER gets the word error rate of the whole corpus.
+func (r Report) WER() float64 {
+	return r.Total.WER()
+}
+
+//SER gets the sentence error rate, the fraction of decoded samples whose hypothesis had errors.
+func (r Report) SER() float64 {
+	decoded := len(r.Files) - r.Failed
+	if decoded == 0 {
+		return 0
+	}
+	return float64(decoded-r.Sentences) / float64(decoded)
+}
+
+//RTF gets the real time factor, decoding time over audio duration.
+func (r Report) RTF() float64 {
+	if r.Audio == 0 {
+		return 0
+	}
+	return r.Elapsed.Seconds() / r.Audio.Seconds()
+}
+
+//WriteTo writes r as text: the alignment and score of each file, then the totals.
+func (r Report) WriteTo(w io.Writer) (int64, error) {
+	cw := &countWriter{w: w}
+	for _, f := range r.Files {
+		if f.Err != nil {
+			fmt.Fprintf(cw, "%s: error %v\n", f.ID, f.Err)
+			continue
+		}
+		ref, hyp := f.Score.AlignedText()
+		fmt.Fprintf(cw, "%s: %s\n  REF: %s\n  HYP: %s\n", f.ID, f.Score, ref, hyp)
+	}
+	fmt.Fprintf(cw, "TOTAL: %s, SER %.2f%% over %d files, %d failed, RTF %.3f\n", r.Total, 100*r.SER(), len(r.Files), r.Failed, r.RTF())
+	return cw.n, cw.err
+}
+
+type countWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
+
+//Run evaluates samples in order. Errors decoding a sample are reported in its FileResult; an error is only returned if ctx is done, with the report of the samples evaluated so far.
+func (e *Evaluator) Run(ctx context.Context, samples []Sample) (Report, error) {
+	var report Report
+	for i, sample := range samples {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		f := e.evaluate(sample)
+		report.Files = append(report.Files, f)
+		if f.Err != nil {
+			report.Failed++
+		} else {
+			report.Total = report.Total.Add(f.Score)
+			if f.Score.Errors() == 0 {
+				report.Sentences++
+			}
+			report.Audio += f.Audio
+			report.Elapsed += f.Elapsed
+		}
+		if e.Progress != nil {
+			e.Progress(i+1, len(samples), f)
+		}
+	}
+	return report, nil
+}
+
+func (e *Evaluator) evaluate(sample Sample) FileResult {
+	f := FileResult{ID: sample.ID, Reference: sample.Reference}
+	rate := e.SampleRate
+	if rate <= 0 {
+		rate = 16000
+	}
+	audio := sample.Audio
+	if audio == nil {
+		var fileRate int
+		audio, fileRate, f.Err = readAudio(sample.Path)
+		if f.Err != nil {
+			return f
+		}
+		if fileRate != 0 && fileRate != rate {
+			f.Err = fmt.Errorf("%s:audio at %dHz, expected %dHz", sample.Path, fileRate, rate)
+			return f
+		}
+	}
+	f.Audio = time.Duration(len(audio)) * time.Second / time.Duration(rate)
+
+	start := time.Now()
+	hyp, err := e.Decoder.DecodeUtterance(audio)
+	f.Elapsed = time.Since(start)
+	if err != nil && !errors.Is(err, decoder.ErrNoHypothesis) {
+		f.Err = err
+		return f
+	}
+	f.Hypothesis = hyp.Text
+
+	normalize := e.Normalize
+	if normalize == nil {
+		normalize = Words
+	}
+	f.Score = AlignWords(normalize(f.Reference), normalize(f.Hypothesis))
+	return f
+}
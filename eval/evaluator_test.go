@@ -0,0 +1,70 @@
+package eval
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/andyleap/pocketsphinx/decoder"
+)
+
+func TestEvaluatorRun(t *testing.T) {
+	d := &decoder.FakeDecoder{Results: []decoder.Result{
+		{Text: "turn on the lights"},
+		{Text: "<s> turn off the light </s>"},
+	}}
+	samples := []Sample{
+		{ID: "exact", Audio: make([]int16, 16000), Reference: "turn on the lights"},
+		{ID: "errors", Audio: make([]int16, 8000), Reference: "turn off the lights"},
+		{ID: "no hypothesis", Audio: make([]int16, 8000), Reference: "stop"},
+		{ID: "missing", Path: "testdata/missing.wav", Reference: "stop"},
+	}
+	report, err := (&Evaluator{Decoder: d}).Run(context.Background(), samples)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		id     string
+		errors int
+		failed bool
+	}{
+		{id: "exact"},
+		{id: "errors", errors: 1},
+		{id: "no hypothesis", errors: 1},
+		{id: "missing", failed: true},
+	}
+	for i, tt := range tests {
+		f := report.Files[i]
+		if f.ID != tt.id || f.Score.Errors() != tt.errors || (f.Err != nil) != tt.failed {
+			t.Errorf("file %d got %s with %d errors and error %v", i, f.ID, f.Score.Errors(), f.Err)
+		}
+	}
+	want := Score{Words: 9, Correct: 7, Substitutions: 1, Deletions: 1}
+	if !reflect.DeepEqual(report.Total, want) {
+		t.Errorf("total got %+v, want %+v", report.Total, want)
+	}
+	if report.Failed != 1 || report.Sentences != 1 {
+		t.Errorf("got %d failed and %d correct sentences", report.Failed, report.Sentences)
+	}
+	if report.Audio != 2*time.Second {
+		t.Errorf("audio got %v, want 2s", report.Audio)
+	}
+	if ser := report.SER(); ser != 2.0/3 {
+		t.Errorf("SER got %v, want %v", ser, 2.0/3)
+	}
+}
+
+func TestEvaluatorRunCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	report, err := (&Evaluator{Decoder: &decoder.FakeDecoder{}}).Run(ctx, []Sample{{ID: "a", Audio: make([]int16, 160)}})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("error %v, want %v", err, context.Canceled)
+	}
+	if len(report.Files) != 0 {
+		t.Errorf("evaluated %d files after cancel", len(report.Files))
+	}
+}
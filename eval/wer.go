@@ -0,0 +1,204 @@
+//Package eval measures the accuracy of speech recognition against reference transcripts, computing the word error rate from the Levenshtein alignment of hypotheses with their references, and running a decoder over a reference corpus:
+//
+//	corpus, err := eval.LoadCorpus("test.fileids", "test.transcription", "wav", ".wav")
+//	report, err := (&eval.Evaluator{Decoder: decoder}).Run(ctx, corpus)
+//	report.WriteTo(os.Stdout)
+//
+//It only depends on the decoder.Decoder interface, so it can be tested with decoder.FakeDecoder.
+package eval
+
+import (
+	"fmt"
+	"strings"
+)
+
+//Op is the kind of an edit aligning a hypothesis with its reference.
+type Op int
+
+const (
+	//Correct is a hypothesis word matching its reference word.
+	Correct Op = iota
+	//Substitution is a hypothesis word replacing a reference word.
+	Substitution
+	//Insertion is a hypothesis word missing from the reference.
+	Insertion
+	//Deletion is a reference word missing from the hypothesis.
+	Deletion
+)
+
+func (o Op) String() string {
+	switch o {
+	case Correct:
+		return "correct"
+	case Substitution:
+		return "substitution"
+	case Insertion:
+		return "insertion"
+	case Deletion:
+		return "deletion"
+	}
+	return fmt.Sprintf("Op(%d)", int(o))
+}
+
+//Edit is a step of an alignment. Ref is empty for insertions and Hyp for deletions.
+type Edit struct {
+	Op  Op     `json:"op"`
+	Ref string `json:"ref,omitempty"`
+	Hyp string `json:"hyp,omitempty"`
+}
+
+//Score counts the errors of hypotheses against their references. Edits is only set by Align, not for aggregates.
+type Score struct {
+	//Words is the number of reference words.
+	Words         int    `json:"words"`
+	Correct       int    `json:"correct"`
+	Substitutions int    `json:"substitutions"`
+	Insertions    int    `json:"insertions"`
+	Deletions     int    `json:"deletions"`
+	Edits         []Edit `json:"edits,omitempty"`
+}
+
+//Errors gets the number of substitutions, insertions and deletions.
+func (s Score) Errors() int {
+	return s.Substitutions + s.Insertions + s.Deletions
+}
+
+//WER gets the word error rate, the number of errors over the number of reference words. It can exceed 1 with many insertions. It is 0 for an empty reference and hypothesis, and 1 for an empty reference with insertions.
+func (s Score) WER() float64 {
+	if s.Words == 0 {
+		if s.Insertions > 0 {
+			return 1
+		}
+		return 0
+	}
+	return float64(s.Errors()) / float64(s.Words)
+}
+
+//Accuracy gets 1-WER, as reported by the sphinx tools.
+func (s Score) Accuracy() float64 {
+	return 1 - s.WER()
+}
+
+//Add gets the sum of the counts of s and o, to aggregate the scores of a corpus.
+func (s Score) Add(o Score) Score {
+	return Score{
+		Words:         s.Words + o.Words,
+		Correct:       s.Correct + o.Correct,
+		Substitutions: s.Substitutions + o.Substitutions,
+		Insertions:    s.Insertions + o.Insertions,
+		Deletions:     s.Deletions + o.Deletions,
+	}
+}
+
+//String formats s like "WER 12.50% (S=1 I=0 D=0 N=8)".
+func (s Score) String() string {
+	return fmt.Sprintf("WER %.2f%% (S=%d I=%d D=%d N=%d)", 100*s.WER(), s.Substitutions, s.Insertions, s.Deletions, s.Words)
+}
+
+//AlignedText formats the alignment of s as two lines of equal width, the reference words above the hypothesis words, with erroneous words in upper case and missing ones as asterisks, as sphinx's word_align.pl does.
+func (s Score) AlignedText() (ref, hyp string) {
+	refs := make([]string, len(s.Edits))
+	hyps := make([]string, len(s.Edits))
+	for i, e := range s.Edits {
+		r, h := e.Ref, e.Hyp
+		if e.Op != Correct {
+			r, h = strings.ToUpper(r), strings.ToUpper(h)
+		}
+		width := len(r)
+		if len(h) > width {
+			width = len(h)
+		}
+		if r == "" {
+			r = strings.Repeat("*", width)
+		}
+		if h == "" {
+			h = strings.Repeat("*", width)
+		}
+		refs[i] = fmt.Sprintf("%-*s", width, r)
+		hyps[i] = fmt.Sprintf("%-*s", width, h)
+	}
+	return strings.TrimRight(strings.Join(refs, " "), " "), strings.TrimRight(strings.Join(hyps, " "), " ")
+}
+
+//Align aligns the words of hyp with those of ref with the fewest errors, counting them. Both are split into words by Words.
+func Align(ref, hyp string) Score {
+	return AlignWords(Words(ref), Words(hyp))
+}
+
+//AlignWords is like Align for text already split into words.
+func AlignWords(ref, hyp []string) Score {
+	//dist[i][j] is the edit distance between ref[:i] and hyp[:j].
+	dist := make([][]int, len(ref)+1)
+	for i := range dist {
+		dist[i] = make([]int, len(hyp)+1)
+		dist[i][0] = i
+	}
+	for j := range dist[0] {
+		dist[0][j] = j
+	}
+	for i := 1; i <= len(ref); i++ {
+		for j := 1; j <= len(hyp); j++ {
+			sub := dist[i-1][j-1]
+			if ref[i-1] != hyp[j-1] {
+				sub++
+			}
+			d := sub
+			if del := dist[i-1][j] + 1; del < d {
+				d = del
+			}
+			if ins := dist[i][j-1] + 1; ins < d {
+				d = ins
+			}
+			dist[i][j] = d
+		}
+	}
+
+	s := Score{Words: len(ref), Edits: make([]Edit, 0, len(ref)+len(hyp))}
+	i, j := len(ref), len(hyp)
+	for i > 0 || j > 0 {
+		switch {
+		case i > 0 && j > 0 && ref[i-1] == hyp[j-1] && dist[i][j] == dist[i-1][j-1]:
+			s.Correct++
+			s.Edits = append(s.Edits, Edit{Op: Correct, Ref: ref[i-1], Hyp: hyp[j-1]})
+			i, j = i-1, j-1
+		case i > 0 && j > 0 && dist[i][j] == dist[i-1][j-1]+1:
+			s.Substitutions++
+			s.Edits = append(s.Edits, Edit{Op: Substitution, Ref: ref[i-1], Hyp: hyp[j-1]})
+			i, j = i-1, j-1
+		case i > 0 && dist[i][j] == dist[i-1][j]+1:
+			s.Deletions++
+			s.Edits = append(s.Edits, Edit{Op: Deletion, Ref: ref[i-1]})
+			i--
+		default:
+			s.Insertions++
+			s.Edits = append(s.Edits, Edit{Op: Insertion, Hyp: hyp[j-1]})
+			j--
+		}
+	}
+	for l, r := 0, len(s.Edits)-1; l < r; l, r = l+1, r-1 {
+		s.Edits[l], s.Edits[r] = s.Edits[r], s.Edits[l]
+	}
+	return s
+}
+
+//Words splits text into lower case words for scoring, dropping sentence markers and fillers such as <s>, <sil> and [NOISE], and the alternative pronunciation suffixes of dictionary words, e.g. read(2).
+func Words(text string) []string {
+	fields := strings.Fields(strings.ToLower(text))
+	ret := fields[:0]
+	for _, w := range fields {
+		if isFiller(w) {
+			continue
+		}
+		if i := strings.IndexByte(w, '('); i > 0 && strings.HasSuffix(w, ")") {
+			w = w[:i]
+		}
+		ret = append(ret, w)
+	}
+	return ret
+}
+
+func isFiller(w string) bool {
+	return strings.HasPrefix(w, "<") && strings.HasSuffix(w, ">") ||
+		strings.HasPrefix(w, "[") && strings.HasSuffix(w, "]") ||
+		strings.HasPrefix(w, "++") && strings.HasSuffix(w, "++")
+}
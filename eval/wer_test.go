@@ -0,0 +1,115 @@
+package eval
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWords(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"Hello World", []string{"hello", "world"}},
+		{"<s> hello <sil> world </s>", []string{"hello", "world"}},
+		{"[NOISE] read(2) the ++BREATH++ book", []string{"read", "the", "book"}},
+		{"  spaced\tout\n", []string{"spaced", "out"}},
+		{"(2)", []string{"(2)"}},
+		{"", []string{}},
+	}
+	for _, tt := range tests {
+		if got := Words(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Words(%q) got %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestAlignWords(t *testing.T) {
+	tests := []struct {
+		name                 string
+		ref, hyp             string
+		correct, sub, ins, d int
+		wer                  float64
+	}{
+		{name: "exact", ref: "turn on the lights", hyp: "turn on the lights", correct: 4},
+		{name: "substitution", ref: "turn on the lights", hyp: "turn off the lights", correct: 3, sub: 1, wer: 0.25},
+		{name: "insertion", ref: "turn on the lights", hyp: "turn on all the lights", correct: 4, ins: 1, wer: 0.25},
+		{name: "deletion", ref: "turn on the lights", hyp: "turn the lights", correct: 3, d: 1, wer: 0.25},
+		{name: "empty hypothesis", ref: "turn on", hyp: "", d: 2, wer: 1},
+		{name: "empty reference", ref: "", hyp: "hello", ins: 1, wer: 1},
+		{name: "both empty", ref: "", hyp: ""},
+		{name: "above one", ref: "hi", hyp: "oh hello there", sub: 1, ins: 2, wer: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Align(tt.ref, tt.hyp)
+			if s.Correct != tt.correct || s.Substitutions != tt.sub || s.Insertions != tt.ins || s.Deletions != tt.d {
+				t.Errorf("got C=%d S=%d I=%d D=%d, want C=%d S=%d I=%d D=%d", s.Correct, s.Substitutions, s.Insertions, s.Deletions, tt.correct, tt.sub, tt.ins, tt.d)
+			}
+			if s.WER() != tt.wer {
+				t.Errorf("WER got %v, want %v", s.WER(), tt.wer)
+			}
+			if s.Accuracy() != 1-tt.wer {
+				t.Errorf("Accuracy got %v, want %v", s.Accuracy(), 1-tt.wer)
+			}
+			if len(s.Edits) != s.Correct+s.Errors() {
+				t.Errorf("got %d edits for %d words", len(s.Edits), s.Correct+s.Errors())
+			}
+		})
+	}
+}
+
+func TestAlignWordsEdits(t *testing.T) {
+	s := AlignWords([]string{"a", "b", "c"}, []string{"a", "x", "c", "d"})
+	want := []Edit{
+		{Op: Correct, Ref: "a", Hyp: "a"},
+		{Op: Substitution, Ref: "b", Hyp: "x"},
+		{Op: Correct, Ref: "c", Hyp: "c"},
+		{Op: Insertion, Hyp: "d"},
+	}
+	if !reflect.DeepEqual(s.Edits, want) {
+		t.Errorf("got edits %+v, want %+v", s.Edits, want)
+	}
+}
+
+func TestScoreText(t *testing.T) {
+	tests := []struct {
+		ref, hyp           string
+		str                string
+		alignRef, alignHyp string
+	}{
+		{
+			ref: "turn on the lights", hyp: "turn off the lights",
+			str:      "WER 25.00% (S=1 I=0 D=0 N=4)",
+			alignRef: "turn ON  the lights", alignHyp: "turn OFF the lights",
+		},
+		{
+			ref: "turn on the lights", hyp: "turn the light",
+			str:      "WER 50.00% (S=1 I=0 D=1 N=4)",
+			alignRef: "turn ON the LIGHTS", alignHyp: "turn ** the LIGHT",
+		},
+		{
+			ref: "stop", hyp: "stop now",
+			str:      "WER 100.00% (S=0 I=1 D=0 N=1)",
+			alignRef: "stop ***", alignHyp: "stop NOW",
+		},
+	}
+	for _, tt := range tests {
+		s := Align(tt.ref, tt.hyp)
+		if got := s.String(); got != tt.str {
+			t.Errorf("%q/%q String got %q, want %q", tt.ref, tt.hyp, got, tt.str)
+		}
+		ref, hyp := s.AlignedText()
+		if ref != tt.alignRef || hyp != tt.alignHyp {
+			t.Errorf("%q/%q AlignedText got\n%q\n%q\nwant\n%q\n%q", tt.ref, tt.hyp, ref, hyp, tt.alignRef, tt.alignHyp)
+		}
+	}
+}
+
+func TestScoreAdd(t *testing.T) {
+	s := Align("a b c d", "a x c").Add(Align("e f", "e f g"))
+	want := Score{Words: 6, Correct: 4, Substitutions: 1, Insertions: 1, Deletions: 1}
+	if !reflect.DeepEqual(s, want) {
+		t.Errorf("got %+v, want %+v", s, want)
+	}
+}
@@ -0,0 +1,211 @@
+//go:build !pocketsphinx5
+
+package pocketsphinx
+
+/*
+#include <pocketsphinx.h>
+#include <stdlib.h>
+cmd_ln_t *default_config();
+static int32 fe_frames(fe_t *fe, int16 const *spch, size_t nsamps, float *out, int32 maxframes){
+    int32 veclen = fe_get_output_size(fe);
+    mfcc_t *buf = malloc(maxframes * veclen * sizeof(mfcc_t));
+    mfcc_t **cep = malloc(maxframes * sizeof(mfcc_t *));
+    int32 i, nframes, total = 0;
+    if (buf == NULL || cep == NULL) {
+        free(buf);
+        free(cep);
+        return -1;
+    }
+    for (i = 0; i < maxframes; ++i)
+        cep[i] = buf + i * veclen;
+    while (nsamps > 0 && total < maxframes) {
+        nframes = maxframes - total;
+        if (fe_process_frames(fe, &spch, &nsamps, cep + total, &nframes, NULL) < 0) {
+            total = -1;
+            break;
+        }
+        if (nframes == 0)
+            break;
+        total += nframes;
+    }
+    for (i = 0; i < total * veclen; ++i)
+        out[i] = MFCC2FLOAT(buf[i]);
+    free(cep);
+    free(buf);
+    return total;
+}
+static int32 fe_last_frame(fe_t *fe, float *out){
+    mfcc_t cep[64];
+    int32 i, nframes = 0;
+    if (fe_get_output_size(fe) > 64 || fe_end_utt(fe, cep, &nframes) < 0)
+        return -1;
+    for (i = 0; nframes > 0 && i < fe_get_output_size(fe); ++i)
+        out[i] = MFCC2FLOAT(cep[i]);
+    return nframes;
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"unsafe"
+)
+
+//FeatureExtractor is the front end computing MFCC frames, the cepstral vectors the acoustic model scores, from 16-bit pcm audio. Features can be cached, inspected, or fed to other models. It must be released with Free, and must not be used concurrently.
+type FeatureExtractor struct {
+	fe    *C.fe_t
+	dim   int
+	shift int
+	size  int
+}
+
+//NewFeatureExtractor creates FeatureExtractor configured by opts like New, e.g. WithSampleRate. The front end parameters of the acoustic model set with WithHMM are read from its feat.params, so the features match those the decoder would compute.
+func NewFeatureExtractor(opts ...Option) (*FeatureExtractor, error) {
+	psConfig := C.default_config()
+	defer C.cmd_ln_free_r(psConfig)
+	for _, opt := range opts {
+		opt(psConfig)
+	}
+	if hmm := getStringParam(psConfig, "-hmm"); hmm != "" {
+		params := filepath.Join(hmm, "feat.params")
+		if _, err := os.Stat(params); err == nil {
+			cparams := C.CString(params)
+			defer C.free(unsafe.Pointer(cparams))
+			var ret *C.cmd_ln_t
+			reason := captureErrors(func() {
+				ret = C.cmd_ln_parse_file_r(psConfig, C.ps_args(), cparams, C.FALSE)
+			})
+			if ret == nil {
+				return nil, fmt.Errorf("feat.params error:%s", reason)
+			}
+		}
+	}
+	var fe *C.fe_t
+	reason := captureErrors(func() {
+		fe = C.fe_init_auto_r(psConfig)
+	})
+	if fe == nil {
+		return nil, fmt.Errorf("fe_init error:%s", reason)
+	}
+	return newFeatureExtractor(fe), nil
+}
+
+//FeatureExtractor gets a FeatureExtractor sharing the configuration of p's front end. It has its own reference to the front end, which stays valid after p is freed, but must not be used while p processes audio.
+func (p *PocketSphinx) FeatureExtractor() (*FeatureExtractor, error) {
+	if err := p.acquire(); err != nil {
+		return nil, err
+	}
+	defer p.release()
+	fe := C.ps_get_fe(p.ps)
+	if fe == nil {
+		return nil, errors.New("fe error:decoder has no front end")
+	}
+	return newFeatureExtractor(C.fe_retain(fe)), nil
+}
+
+func newFeatureExtractor(fe *C.fe_t) *FeatureExtractor {
+	f := &FeatureExtractor{fe: fe, dim: int(C.fe_get_output_size(fe))}
+	var shift, size C.int
+	C.fe_get_input_size(fe, &shift, &size)
+	f.shift, f.size = int(shift), int(size)
+	return f
+}
+
+//Free releases the front end.
+func (f *FeatureExtractor) Free() {
+	if f.fe != nil {
+		C.fe_free(f.fe)
+		f.fe = nil
+	}
+}
+
+//Dim gets the number of coefficients of a frame, 13 for most models.
+func (f *FeatureExtractor) Dim() int {
+	return f.dim
+}
+
+//FrameShift gets the number of samples between the starts of consecutive frames.
+func (f *FeatureExtractor) FrameShift() int {
+	return f.shift
+}
+
+//FrameSize gets the number of samples a frame is computed from.
+func (f *FeatureExtractor) FrameSize() int {
+	return f.size
+}
+
+//StartUtt starts a new utterance, discarding the audio buffered from the previous one.
+func (f *FeatureExtractor) StartUtt() error {
+	if f.fe == nil {
+		return ErrClosed
+	}
+	if ret := C.fe_start_utt(f.fe); ret < 0 {
+		return fmt.Errorf("fe_start_utt error:%d", ret)
+	}
+	return nil
+}
+
+//ProcessRaw computes the frames of the next block of audio of the utterance. Samples not filling a whole frame are buffered until the next call or EndUtt.
+func (f *FeatureExtractor) ProcessRaw(audio []int16) ([][]float32, error) {
+	if f.fe == nil {
+		return nil, ErrClosed
+	}
+	if len(audio) == 0 {
+		return nil, nil
+	}
+	maxFrames := (len(audio)+f.size)/f.shift + 1
+	out := make([]C.float, maxFrames*f.dim)
+	n := C.fe_frames(f.fe, (*C.int16)(unsafe.Pointer(&audio[0])), C.size_t(len(audio)), &out[0], C.int32(maxFrames))
+	if n < 0 {
+		return nil, errors.New("fe_process_frames error")
+	}
+	return f.frames(out, int(n)), nil
+}
+
+//EndUtt ends the utterance, returning the last frame computed from the buffered audio, if any.
+func (f *FeatureExtractor) EndUtt() ([][]float32, error) {
+	if f.fe == nil {
+		return nil, ErrClosed
+	}
+	out := make([]C.float, maxCepSize)
+	n := C.fe_last_frame(f.fe, &out[0])
+	if n < 0 {
+		return nil, errors.New("fe_end_utt error")
+	}
+	return f.frames(out, int(n)), nil
+}
+
+//Process computes the frames of audio as a whole utterance.
+func (f *FeatureExtractor) Process(audio []int16) ([][]float32, error) {
+	if err := f.StartUtt(); err != nil {
+		return nil, err
+	}
+	frames, err := f.ProcessRaw(audio)
+	if err != nil {
+		return nil, err
+	}
+	last, err := f.EndUtt()
+	if err != nil {
+		return nil, err
+	}
+	return append(frames, last...), nil
+}
+
+//frames splits the first n frames of out into vectors.
+func (f *FeatureExtractor) frames(out []C.float, n int) [][]float32 {
+	if n == 0 {
+		return nil
+	}
+	data := make([]float32, n*f.dim)
+	for i := range data {
+		data[i] = float32(out[i])
+	}
+	ret := make([][]float32, n)
+	for i := range ret {
+		ret[i] = data[i*f.dim : (i+1)*f.dim : (i+1)*f.dim]
+	}
+	return ret
+}
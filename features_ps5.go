@@ -0,0 +1,58 @@
+//go:build pocketsphinx5
+
+package pocketsphinx
+
+import (
+	"fmt"
+)
+
+//FeatureExtractor is the front end computing MFCC frames. pocketsphinx 5 doesn't expose its front end, so it is unavailable.
+type FeatureExtractor struct{}
+
+//NewFeatureExtractor returns ErrUnsupported with pocketsphinx 5.
+func NewFeatureExtractor(opts ...Option) (*FeatureExtractor, error) {
+	return nil, fmt.Errorf("fe error:%w", ErrUnsupported)
+}
+
+//FeatureExtractor returns ErrUnsupported with pocketsphinx 5.
+func (p *PocketSphinx) FeatureExtractor() (*FeatureExtractor, error) {
+	return nil, fmt.Errorf("fe error:%w", ErrUnsupported)
+}
+
+//Free does nothing.
+func (f *FeatureExtractor) Free() {}
+
+//Dim returns 0.
+func (f *FeatureExtractor) Dim() int {
+	return 0
+}
+
+//FrameShift returns 0.
+func (f *FeatureExtractor) FrameShift() int {
+	return 0
+}
+
+//FrameSize returns 0.
+func (f *FeatureExtractor) FrameSize() int {
+	return 0
+}
+
+//StartUtt returns ErrUnsupported.
+func (f *FeatureExtractor) StartUtt() error {
+	return ErrUnsupported
+}
+
+//ProcessRaw returns ErrUnsupported.
+func (f *FeatureExtractor) ProcessRaw(audio []int16) ([][]float32, error) {
+	return nil, ErrUnsupported
+}
+
+//EndUtt returns ErrUnsupported.
+func (f *FeatureExtractor) EndUtt() ([][]float32, error) {
+	return nil, ErrUnsupported
+}
+
+//Process returns ErrUnsupported.
+func (f *FeatureExtractor) Process(audio []int16) ([][]float32, error) {
+	return nil, ErrUnsupported
+}
@@ -0,0 +1,73 @@
+package pocketsphinx
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+//NewFromFS creates PocketSphinx instance configured by cfg, whose model paths (HMM, Dict, FDict, LM, LMCtl, JSGF, FSG, Kws and Allphone) name files and directories in fsys rather than on disk, e.g. models embedded in the binary with go:embed. As sphinx can only read files on disk, they are extracted to a temporary directory, which is removed by Free.
+func NewFromFS(fsys fs.FS, cfg Config) (*PocketSphinx, error) {
+	dir, err := os.MkdirTemp("", "pocketsphinx-")
+	if err != nil {
+		return nil, fmt.Errorf("fs error:%v", err)
+	}
+	for _, field := range []*string{&cfg.HMM, &cfg.Dict, &cfg.FDict, &cfg.LM, &cfg.LMCtl, &cfg.JSGF, &cfg.FSG, &cfg.Kws, &cfg.Allphone} {
+		if *field == "" {
+			continue
+		}
+		extracted, err := extractFS(fsys, *field, dir)
+		if err != nil {
+			os.RemoveAll(dir)
+			return nil, fmt.Errorf("fs error:%v", err)
+		}
+		*field = extracted
+	}
+	p, err := NewFromConfig(cfg)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	p.tmpDir = dir
+	return p, nil
+}
+
+//extractFS copies the file or directory name of fsys to the same path under dir, returning its path on disk.
+func extractFS(fsys fs.FS, name, dir string) (string, error) {
+	name = path.Clean(name)
+	dst := filepath.Join(dir, filepath.FromSlash(name))
+	err := fs.WalkDir(fsys, name, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dir, filepath.FromSlash(p))
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		return copyFSFile(fsys, p, target)
+	})
+	return dst, err
+}
+
+func copyFSFile(fsys fs.FS, name, dst string) error {
+	src, err := fsys.Open(name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, src); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,157 @@
+package pocketsphinx
+
+/*
+#include <pocketsphinx.h>
+#include <stdlib.h>
+void fsg_set_states(fsg_model_t *fsg, int start, int final){
+    fsg->start_state = start;
+    fsg->final_state = final;
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unsafe"
+)
+
+//FSG is a finite state grammar built in Go, e.g. from a list of device names loaded at runtime, to be added as a search with SetFSG. States are numbered from 0, and transitions are labeled with a word, or with no word for null transitions.
+type FSG struct {
+	Start   int
+	Final   int
+	states  int
+	trans   []fsgTrans
+	silence []fsgSilence
+}
+
+type fsgTrans struct {
+	from, to int
+	word     string
+	prob     float64
+}
+
+type fsgSilence struct {
+	state int
+	prob  float64
+}
+
+//NewFSG creates an FSG with numStates states, starting in state 0 and ending in state numStates-1.
+func NewFSG(numStates int) *FSG {
+	return &FSG{Start: 0, Final: numStates - 1, states: numStates}
+}
+
+//NumStates gets the number of states of the grammar.
+func (f *FSG) NumStates() int {
+	return f.states
+}
+
+//AddState adds a state to the grammar and returns its number.
+func (f *FSG) AddState() int {
+	f.states++
+	return f.states - 1
+}
+
+//AddTransition adds a transition from state from to state to, emitting word with probability prob.
+func (f *FSG) AddTransition(from, to int, word string, prob float64) {
+	f.trans = append(f.trans, fsgTrans{from: from, to: to, word: word, prob: prob})
+}
+
+//AddNull adds a null transition, emitting no word, from state from to state to with probability prob.
+func (f *FSG) AddNull(from, to int, prob float64) {
+	f.trans = append(f.trans, fsgTrans{from: from, to: to, prob: prob})
+}
+
+//AddPhrase adds a path from state from to state to emitting the words of phrase in order, adding a state between each word. prob is given to the first transition, the following ones have probability 1.
+func (f *FSG) AddPhrase(from, to int, phrase string, prob float64) {
+	words := strings.Fields(phrase)
+	for i, word := range words {
+		next := to
+		if i < len(words)-1 {
+			next = f.AddState()
+		}
+		f.AddTransition(from, next, word, prob)
+		from, prob = next, 1
+	}
+}
+
+//AddSilence adds optional silence self-loops with probability prob to state, or to all states if state is negative.
+func (f *FSG) AddSilence(state int, prob float64) {
+	f.silence = append(f.silence, fsgSilence{state: state, prob: prob})
+}
+
+func (f *FSG) validate() error {
+	if f.states <= 0 {
+		return errors.New("no states")
+	}
+	if f.Start < 0 || f.Start >= f.states || f.Final < 0 || f.Final >= f.states {
+		return fmt.Errorf("start %d or final %d state out of range", f.Start, f.Final)
+	}
+	for _, t := range f.trans {
+		if t.from < 0 || t.from >= f.states || t.to < 0 || t.to >= f.states {
+			return fmt.Errorf("transition %d->%d out of range", t.from, t.to)
+		}
+		if t.prob <= 0 || t.prob > 1 {
+			return fmt.Errorf("transition %d->%d has probability %v", t.from, t.to, t.prob)
+		}
+	}
+	for _, s := range f.silence {
+		if s.state >= f.states {
+			return fmt.Errorf("silence state %d out of range", s.state)
+		}
+	}
+	return nil
+}
+
+//SetFSG adds a search named name for the finite state grammar f. Transition probabilities are scaled by the language weight (-lw) like those of grammars read from files. It returns ErrWordNotInDict if f uses words missing from the dictionary, and ErrBadGrammar if f is not a valid grammar.
+func (p *PocketSphinx) SetFSG(name string, f *FSG) error {
+	if err := p.acquire(); err != nil {
+		return err
+	}
+	defer p.release()
+	if err := f.validate(); err != nil {
+		return fmt.Errorf("fsg %s error:%w:%v", name, ErrBadGrammar, err)
+	}
+	lmath := p.logMath()
+	lw := getFloatParam(C.ps_get_config(p.ps), "-lw")
+
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	fsg := C.fsg_model_init(cname, lmath.lmath, C.float32(lw), C.int32(f.states))
+	defer C.fsg_model_free(fsg)
+	C.fsg_set_states(fsg, C.int(f.Start), C.int(f.Final))
+
+	wids := make(map[string]C.int)
+	for _, t := range f.trans {
+		logp := C.int32(float64(lmath.Log(t.prob)) * lw)
+		if t.word == "" {
+			C.fsg_model_null_trans_add(fsg, C.int32(t.from), C.int32(t.to), logp)
+			continue
+		}
+		wid, ok := wids[t.word]
+		if !ok {
+			cword := C.CString(t.word)
+			wid = C.fsg_model_word_add(fsg, cword)
+			C.free(unsafe.Pointer(cword))
+			wids[t.word] = wid
+		}
+		C.fsg_model_trans_add(fsg, C.int32(t.from), C.int32(t.to), logp, C.int32(wid))
+	}
+	if len(f.silence) > 0 {
+		silword := C.CString("<sil>")
+		defer C.free(unsafe.Pointer(silword))
+		for _, s := range f.silence {
+			C.fsg_model_add_silence(fsg, silword, C.int(s.state), C.float32(s.prob))
+		}
+	}
+
+	var ret C.int
+	reason := captureErrors(func() {
+		ret = C.ps_set_fsg(p.ps, cname, fsg)
+	})
+	if ret != 0 {
+		return searchError("fsg", name, ErrBadGrammar, reason)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package pocketsphinx
+
+//mulawTable and alawTable map G.711 code words to 16-bit linear pcm.
+var mulawTable, alawTable [256]int16
+
+func init() {
+	for i := range mulawTable {
+		mulawTable[i] = mulawDecode(byte(i))
+		alawTable[i] = alawDecode(byte(i))
+	}
+}
+
+func mulawDecode(u byte) int16 {
+	u = ^u
+	exponent := (u >> 4) & 0x07
+	mantissa := int32(u & 0x0F)
+	sample := ((mantissa << 3) + 0x84) << exponent
+	sample -= 0x84
+	if u&0x80 != 0 {
+		return int16(-sample)
+	}
+	return int16(sample)
+}
+
+func alawDecode(a byte) int16 {
+	a ^= 0x55
+	exponent := (a >> 4) & 0x07
+	mantissa := int32(a & 0x0F)
+	var sample int32
+	if exponent == 0 {
+		sample = (mantissa << 4) + 8
+	} else {
+		sample = ((mantissa << 4) + 0x108) << (exponent - 1)
+	}
+	if a&0x80 != 0 {
+		return int16(sample)
+	}
+	return int16(-sample)
+}
+
+//MulawToPCM appends the G.711 µ-law encoded audio in data to dst as 16-bit linear pcm.
+func MulawToPCM(dst []int16, data []byte) []int16 {
+	for _, b := range data {
+		dst = append(dst, mulawTable[b])
+	}
+	return dst
+}
+
+//AlawToPCM appends the G.711 A-law encoded audio in data to dst as 16-bit linear pcm.
+func AlawToPCM(dst []int16, data []byte) []int16 {
+	for _, b := range data {
+		dst = append(dst, alawTable[b])
+	}
+	return dst
+}
+
+//ProcessMulaw processes G.711 µ-law encoded audio, as delivered by telephony systems, like ProcessRaw.
+func (p *PocketSphinx) ProcessMulaw(data []byte, noSearch, fullUtt bool) (int, error) {
+	if err := p.acquire(); err != nil {
+		return 0, err
+	}
+	defer p.release()
+	p.conv = MulawToPCM(p.conv[:0], data)
+	return p.processRaw(p.conv, noSearch, fullUtt)
+}
+
+//ProcessAlaw processes G.711 A-law encoded audio, as delivered by telephony systems, like ProcessRaw.
+func (p *PocketSphinx) ProcessAlaw(data []byte, noSearch, fullUtt bool) (int, error) {
+	if err := p.acquire(); err != nil {
+		return 0, err
+	}
+	defer p.release()
+	p.conv = AlawToPCM(p.conv[:0], data)
+	return p.processRaw(p.conv, noSearch, fullUtt)
+}
@@ -0,0 +1,50 @@
+package pocketsphinx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestG711Tables(t *testing.T) {
+	tests := []struct {
+		name   string
+		decode func(dst []int16, data []byte) []int16
+		code   byte
+		want   int16
+	}{
+		{"mu-law positive zero", MulawToPCM, 0xFF, 0},
+		{"mu-law negative zero", MulawToPCM, 0x7F, 0},
+		{"mu-law smallest step", MulawToPCM, 0xFE, 8},
+		{"mu-law positive peak", MulawToPCM, 0x80, 32124},
+		{"mu-law negative peak", MulawToPCM, 0x00, -32124},
+		{"A-law smallest positive", AlawToPCM, 0xD5, 8},
+		{"A-law smallest negative", AlawToPCM, 0x55, -8},
+		{"A-law positive peak", AlawToPCM, 0xAA, 32256},
+		{"A-law negative peak", AlawToPCM, 0x2A, -32256},
+	}
+	for _, tt := range tests {
+		if got := tt.decode(nil, []byte{tt.code}); len(got) != 1 || got[0] != tt.want {
+			t.Errorf("%s: %#02x got %v, want %d", tt.name, tt.code, got, tt.want)
+		}
+	}
+
+	//The sign bit mirrors every code word.
+	for i := 0; i < 128; i++ {
+		if mulawTable[i] != -mulawTable[i|0x80] {
+			t.Errorf("mu-law %#02x is %d, %#02x is %d", i, mulawTable[i], i|0x80, mulawTable[i|0x80])
+		}
+		if alawTable[i] != -alawTable[i|0x80] {
+			t.Errorf("A-law %#02x is %d, %#02x is %d", i, alawTable[i], i|0x80, alawTable[i|0x80])
+		}
+	}
+}
+
+func TestG711Append(t *testing.T) {
+	dst := []int16{1}
+	if got, want := MulawToPCM(dst, []byte{0xFF, 0x80}), []int16{1, 0, 32124}; !reflect.DeepEqual(got, want) {
+		t.Errorf("MulawToPCM got %v, want %v", got, want)
+	}
+	if got, want := AlawToPCM(dst, []byte{0xD5, 0x55}), []int16{1, 8, -8}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AlawToPCM got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,248 @@
+//Package grammar builds JSGF grammars in Go code, so that they are checked before being passed to the decoder.
+//
+//	g := grammar.New("commands")
+//	g.Rule("command").Public().Is(grammar.Ref("device"), grammar.Alt(grammar.Words("on"), grammar.Words("off")))
+//	g.Rule("device").OneOf("lights", "fan")
+//	err := g.Register(decoder)
+package grammar
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+//Registerer adds a named JSGF grammar search to a decoder. *pocketsphinx.PocketSphinx implements it.
+type Registerer interface {
+	ParseJSGF(name string, grammar string) error
+}
+
+//Grammar is a JSGF grammar under construction.
+type Grammar struct {
+	name  string
+	rules []*Rule
+}
+
+//Rule is a rule of a Grammar.
+type Rule struct {
+	name   string
+	public bool
+	expr   Expr
+}
+
+//Expr is a rule expansion.
+type Expr interface {
+	jsgf() string
+	check(g *Grammar) error
+}
+
+//New creates Grammar named name.
+func New(name string) *Grammar {
+	return &Grammar{name: name}
+}
+
+//Rule gets the rule named name, adding it if it doesn't exist yet.
+func (g *Grammar) Rule(name string) *Rule {
+	for _, r := range g.rules {
+		if r.name == name {
+			return r
+		}
+	}
+	r := &Rule{name: name}
+	g.rules = append(g.rules, r)
+	return r
+}
+
+//Public makes the rule public, so it can be the top rule of the search. If no rule is public, the first rule is.
+func (r *Rule) Public() *Rule {
+	r.public = true
+	return r
+}
+
+//Is sets the expansion of the rule to the sequence exprs.
+func (r *Rule) Is(exprs ...Expr) *Rule {
+	r.expr = Seq(exprs...)
+	return r
+}
+
+//OneOf sets the expansion of the rule to one of the phrases.
+func (r *Rule) OneOf(phrases ...string) *Rule {
+	alts := make([]Expr, len(phrases))
+	for i, phrase := range phrases {
+		alts[i] = Words(phrase)
+	}
+	r.expr = Alt(alts...)
+	return r
+}
+
+//Build checks the grammar and returns it as JSGF text.
+func (g *Grammar) Build() (string, error) {
+	if !isName(g.name) {
+		return "", fmt.Errorf("grammar %q error:invalid name", g.name)
+	}
+	if len(g.rules) == 0 {
+		return "", fmt.Errorf("grammar %s error:no rules", g.name)
+	}
+	anyPublic := false
+	for _, r := range g.rules {
+		if !isName(r.name) {
+			return "", fmt.Errorf("grammar %s error:invalid rule name %q", g.name, r.name)
+		}
+		if r.expr == nil {
+			return "", fmt.Errorf("grammar %s error:rule %s has no expansion", g.name, r.name)
+		}
+		if err := r.expr.check(g); err != nil {
+			return "", fmt.Errorf("grammar %s error:rule %s:%v", g.name, r.name, err)
+		}
+		anyPublic = anyPublic || r.public
+	}
+
+	var b strings.Builder
+	b.WriteString("#JSGF V1.0;\n")
+	fmt.Fprintf(&b, "grammar %s;\n", g.name)
+	for i, r := range g.rules {
+		if r.public || (!anyPublic && i == 0) {
+			b.WriteString("public ")
+		}
+		fmt.Fprintf(&b, "<%s> = %s;\n", r.name, r.expr.jsgf())
+	}
+	return b.String(), nil
+}
+
+//String returns the grammar as JSGF text, or an empty string if it doesn't build.
+func (g *Grammar) String() string {
+	text, _ := g.Build()
+	return text
+}
+
+//Register builds the grammar and adds it to r as a search named after the grammar.
+func (g *Grammar) Register(r Registerer) error {
+	text, err := g.Build()
+	if err != nil {
+		return err
+	}
+	return r.ParseJSGF(g.name, text)
+}
+
+type words []string
+
+//Words matches the words of phrase in order.
+func Words(phrase string) Expr {
+	return words(strings.Fields(phrase))
+}
+
+func (w words) jsgf() string {
+	return strings.Join(w, " ")
+}
+
+func (w words) check(g *Grammar) error {
+	if len(w) == 0 {
+		return errors.New("empty phrase")
+	}
+	for _, word := range w {
+		if strings.ContainsAny(word, reserved) {
+			return fmt.Errorf("word %q contains a reserved character", word)
+		}
+	}
+	return nil
+}
+
+type ref string
+
+//Ref matches the rule named name.
+func Ref(name string) Expr {
+	return ref(name)
+}
+
+func (r ref) jsgf() string {
+	return "<" + string(r) + ">"
+}
+
+func (r ref) check(g *Grammar) error {
+	for _, rule := range g.rules {
+		if rule.name == string(r) {
+			return nil
+		}
+	}
+	return fmt.Errorf("undefined rule %s", string(r))
+}
+
+type group struct {
+	exprs []Expr
+	sep   string
+	open  string
+	close string
+}
+
+//Seq matches exprs in order.
+func Seq(exprs ...Expr) Expr {
+	return group{exprs: exprs, sep: " ", open: "(", close: ")"}
+}
+
+//Alt matches one of exprs.
+func Alt(exprs ...Expr) Expr {
+	return group{exprs: exprs, sep: " | ", open: "(", close: ")"}
+}
+
+//Optional matches exprs in order, or nothing.
+func Optional(exprs ...Expr) Expr {
+	return group{exprs: exprs, sep: " ", open: "[", close: "]"}
+}
+
+func (gr group) jsgf() string {
+	parts := make([]string, len(gr.exprs))
+	for i, e := range gr.exprs {
+		parts[i] = e.jsgf()
+	}
+	return gr.open + strings.Join(parts, gr.sep) + gr.close
+}
+
+func (gr group) check(g *Grammar) error {
+	if len(gr.exprs) == 0 {
+		return errors.New("empty group")
+	}
+	for _, e := range gr.exprs {
+		if err := e.check(g); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type repeat struct {
+	expr Expr
+	op   string
+}
+
+//Repeat matches expr one or more times.
+func Repeat(expr Expr) Expr {
+	return repeat{expr: expr, op: "+"}
+}
+
+//Any matches expr zero or more times.
+func Any(expr Expr) Expr {
+	return repeat{expr: expr, op: "*"}
+}
+
+func (r repeat) jsgf() string {
+	return "(" + r.expr.jsgf() + ")" + r.op
+}
+
+func (r repeat) check(g *Grammar) error {
+	return r.expr.check(g)
+}
+
+//reserved are the characters with a meaning in JSGF that can't appear in words.
+const reserved = ";=|*+<>()[]{}/\"\\"
+
+func isName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		if !(c == '_' || c == '.' || c == '-' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,139 @@
+package grammar
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func() *Grammar
+		want  string
+		err   string
+	}{
+		{
+			name: "first rule public",
+			build: func() *Grammar {
+				g := New("commands")
+				g.Rule("command").Is(Ref("device"), Alt(Words("on"), Words("off")))
+				g.Rule("device").OneOf("lights", "ceiling fan")
+				return g
+			},
+			want: "#JSGF V1.0;\ngrammar commands;\npublic <command> = (<device> (on | off));\n<device> = (lights | ceiling fan);\n",
+		},
+		{
+			name: "explicit public",
+			build: func() *Grammar {
+				g := New("g")
+				g.Rule("filler").OneOf("please")
+				g.Rule("top").Public().Is(Optional(Ref("filler")), Repeat(Words("go")), Any(Words("now")))
+				return g
+			},
+			want: "#JSGF V1.0;\ngrammar g;\n<filler> = (please);\npublic <top> = ([<filler>] (go)+ (now)*);\n",
+		},
+		{
+			name: "rule redefined",
+			build: func() *Grammar {
+				g := New("g")
+				g.Rule("top").OneOf("yes")
+				g.Rule("top").OneOf("no")
+				return g
+			},
+			want: "#JSGF V1.0;\ngrammar g;\npublic <top> = (no);\n",
+		},
+		{
+			name:  "invalid name",
+			build: func() *Grammar { g := New("my grammar"); g.Rule("top").OneOf("yes"); return g },
+			err:   "invalid name",
+		},
+		{
+			name:  "no rules",
+			build: func() *Grammar { return New("g") },
+			err:   "no rules",
+		},
+		{
+			name:  "invalid rule name",
+			build: func() *Grammar { g := New("g"); g.Rule("top<").OneOf("yes"); return g },
+			err:   "invalid rule name",
+		},
+		{
+			name:  "no expansion",
+			build: func() *Grammar { g := New("g"); g.Rule("top"); return g },
+			err:   "has no expansion",
+		},
+		{
+			name:  "empty phrase",
+			build: func() *Grammar { g := New("g"); g.Rule("top").OneOf("yes", " "); return g },
+			err:   "empty phrase",
+		},
+		{
+			name:  "reserved character",
+			build: func() *Grammar { g := New("g"); g.Rule("top").OneOf("yes|no"); return g },
+			err:   "reserved character",
+		},
+		{
+			name:  "undefined rule",
+			build: func() *Grammar { g := New("g"); g.Rule("top").Is(Ref("device")); return g },
+			err:   "undefined rule device",
+		},
+		{
+			name:  "empty group",
+			build: func() *Grammar { g := New("g"); g.Rule("top").Is(Alt()); return g },
+			err:   "empty group",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := tt.build()
+			got, err := g.Build()
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("Build error %v, want %q", err, tt.err)
+				}
+				if g.String() != "" {
+					t.Errorf("String of an invalid grammar got %q", g.String())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Build got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+type fakeRegisterer struct {
+	name, text string
+	err        error
+}
+
+func (f *fakeRegisterer) ParseJSGF(name string, grammar string) error {
+	f.name, f.text = name, grammar
+	return f.err
+}
+
+func TestRegister(t *testing.T) {
+	g := New("commands")
+	g.Rule("top").OneOf("yes", "no")
+	r := &fakeRegisterer{}
+	if err := g.Register(r); err != nil {
+		t.Fatal(err)
+	}
+	if r.name != "commands" || r.text != g.String() {
+		t.Errorf("registered %q as %q", r.text, r.name)
+	}
+
+	r = &fakeRegisterer{err: errors.New("parse failed")}
+	if err := g.Register(r); err != r.err {
+		t.Errorf("Register error %v, want %v", err, r.err)
+	}
+	r = &fakeRegisterer{}
+	if err := New("empty").Register(r); err == nil || r.name != "" {
+		t.Errorf("invalid grammar registered, error %v", err)
+	}
+}
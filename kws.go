@@ -0,0 +1,125 @@
+package pocketsphinx
+
+/*
+#include <pocketsphinx.h>
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"unsafe"
+)
+
+//Keyword is a phrase to spot with a keyword list search. Threshold is the detection threshold of the phrase, typically between 1e-50 and 1, lower values detecting the phrase more often. If it is zero, -kws_threshold is used.
+type Keyword struct {
+	Phrase    string  `json:"phrase" yaml:"phrase"`
+	Threshold float64 `json:"threshold,omitempty" yaml:"threshold,omitempty"`
+}
+
+//SetKeywordList adds a keyword spotting search named name for several phrases, each with its own threshold. It returns ErrWordNotInDict if a phrase uses words missing from the dictionary.
+func (p *PocketSphinx) SetKeywordList(name string, kws []Keyword) error {
+	if err := p.acquire(); err != nil {
+		return err
+	}
+	defer p.release()
+	if len(kws) == 0 {
+		return fmt.Errorf("kws %s error:no keywords", name)
+	}
+	f, err := os.CreateTemp("", "kws-*.list")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if err := writeKeywords(f, kws); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return p.setKws(name, f.Name())
+}
+
+//SetKwsFile adds a keyword spotting search named name for the keyword list in the file at path, with one phrase per line optionally followed by its threshold between slashes, as read by -kws. Errors are reported as by SetKeywordList.
+func (p *PocketSphinx) SetKwsFile(name, path string) error {
+	if err := p.acquire(); err != nil {
+		return err
+	}
+	defer p.release()
+	if _, err := os.Stat(path); err != nil {
+		return err
+	}
+	return p.setKws(name, path)
+}
+
+func (p *PocketSphinx) setKws(name, path string) error {
+	cname := C.CString(name)
+	cpath := C.CString(path)
+	defer C.free(unsafe.Pointer(cname))
+	defer C.free(unsafe.Pointer(cpath))
+	var ret C.int
+	reason := captureErrors(func() {
+		ret = C.ps_set_kws(p.ps, cname, cpath)
+	})
+	if ret != 0 {
+		return searchError("kws", name, ErrBadGrammar, reason)
+	}
+	return nil
+}
+
+//writeKeywords writes kws in the keyword list format read by -kws, one phrase per line followed by its threshold between slashes.
+func writeKeywords(f *os.File, kws []Keyword) error {
+	w := bufio.NewWriter(f)
+	for _, kw := range kws {
+		phrase := strings.Join(strings.Fields(kw.Phrase), " ")
+		if phrase == "" || strings.Contains(phrase, "/") {
+			return fmt.Errorf("kws error:invalid phrase %q", kw.Phrase)
+		}
+		if kw.Threshold < 0 {
+			return fmt.Errorf("kws error:invalid threshold %v for %q", kw.Threshold, phrase)
+		}
+		if kw.Threshold == 0 {
+			fmt.Fprintln(w, phrase)
+			continue
+		}
+		fmt.Fprintf(w, "%s /%g/\n", phrase, kw.Threshold)
+	}
+	return w.Flush()
+}
+
+//Detection is a keyphrase spotted by a keyword search, with its start and end time in seconds and its detection score in the log domain, higher scores being more confident.
+type Detection struct {
+	Keyword string  `json:"keyword"`
+	Start   float64 `json:"start"`
+	End     float64 `json:"end"`
+	Score   int64   `json:"score"`
+}
+
+//GetKeywordDetections gets the keyphrases detected so far in the utterance by the active search, which must be a keyword search.
+func (p *PocketSphinx) GetKeywordDetections() ([]Detection, error) {
+	if err := p.acquire(); err != nil {
+		return nil, err
+	}
+	defer p.release()
+	search := C.ps_get_search(p.ps)
+	if search == nil || C.ps_get_kws(p.ps, search) == nil {
+		return nil, fmt.Errorf("search %s error:not a keyword search", C.GoString(search))
+	}
+
+	var ret []Detection
+	frate := p.frameRate()
+	for seg := C.ps_seg_iter(p.ps); seg != nil; seg = C.ps_seg_next(seg) {
+		s := getSegment(seg, "")
+		ret = append(ret, Detection{
+			Keyword: s.Word,
+			Start:   float64(s.StartFrame) / frate,
+			End:     float64(s.EndFrame+1) / frate,
+			Score:   s.Prob,
+		})
+	}
+	return ret, nil
+}
@@ -0,0 +1,270 @@
+package pocketsphinx
+
+/*
+#include <pocketsphinx.h>
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"unsafe"
+)
+
+//Lattice is a word lattice of the last utterance.
+type Lattice struct {
+	dag *C.ps_lattice_t
+}
+
+//LatNode is a node of a Lattice, a word ending in a range of frames.
+type LatNode struct {
+	lat  *Lattice
+	node *C.ps_latnode_t
+}
+
+//LatLink is a link of a Lattice, a word spanning from one node to another.
+type LatLink struct {
+	lat  *Lattice
+	link *C.ps_latlink_t
+}
+
+//GetLattice gets the word lattice of the last utterance. It stays valid after further decoding and must be released with Free.
+func (p *PocketSphinx) GetLattice() (*Lattice, error) {
+	if err := p.acquire(); err != nil {
+		return nil, err
+	}
+	defer p.release()
+	dag := C.ps_get_lattice(p.ps)
+	if dag == nil {
+		return nil, errors.New("no lattice")
+	}
+	return &Lattice{dag: C.ps_lattice_retain(dag)}, nil
+}
+
+//Free releases the lattice. It is safe to call more than once; afterwards its nodes and links are invalid, the accessors return zero values and the writers ErrClosed.
+func (l *Lattice) Free() {
+	if l.dag == nil {
+		return
+	}
+	C.ps_lattice_free(l.dag)
+	l.dag = nil
+}
+
+//freed reports whether l is nil or was freed, so that nodes and links referencing it mustn't be used.
+func (l *Lattice) freed() bool {
+	return l == nil || l.dag == nil
+}
+
+//NumFrames gets the number of frames spanned by the lattice.
+func (l *Lattice) NumFrames() int {
+	if l.freed() {
+		return 0
+	}
+	return int(C.ps_lattice_n_frames(l.dag))
+}
+
+//Posterior computes the posterior probabilities of all nodes and links with a forward-backward pass over the lattice, scaling acoustic scores by ascale. It returns the log probability of all paths.
+func (l *Lattice) Posterior(ascale float64) int64 {
+	if l.freed() {
+		return 0
+	}
+	return int64(C.ps_lattice_posterior(l.dag, nil, C.float32(ascale)))
+}
+
+//WriteHTK writes the lattice to path in HTK Standard Lattice Format.
+func (l *Lattice) WriteHTK(path string) error {
+	if l.freed() {
+		return ErrClosed
+	}
+	cpath := C.CString(path)
+	defer C.free(unsafe.Pointer(cpath))
+	if C.ps_lattice_write_htk(l.dag, cpath) < 0 {
+		return fmt.Errorf("lattice_write_htk error:%s", path)
+	}
+	return nil
+}
+
+//Write writes the lattice to path in the native Sphinx format.
+func (l *Lattice) Write(path string) error {
+	if l.freed() {
+		return ErrClosed
+	}
+	cpath := C.CString(path)
+	defer C.free(unsafe.Pointer(cpath))
+	if C.ps_lattice_write(l.dag, cpath) < 0 {
+		return fmt.Errorf("lattice_write error:%s", path)
+	}
+	return nil
+}
+
+//WriteDOT writes the lattice to w as a Graphviz digraph. Nodes are labeled with their word and frame range, links with their word, frames, log posterior and acoustic score.
+func (l *Lattice) WriteDOT(w io.Writer) error {
+	if l.freed() {
+		return ErrClosed
+	}
+	bw := bufio.NewWriter(w)
+	nodes := l.Nodes()
+	ids := make(map[*C.ps_latnode_t]int, len(nodes))
+	for i, n := range nodes {
+		ids[n.node] = i
+	}
+
+	fmt.Fprintln(bw, "digraph lattice {")
+	fmt.Fprintln(bw, "\trankdir=LR;")
+	for i, n := range nodes {
+		start, firstEnd, lastEnd := n.Times()
+		label := fmt.Sprintf("%s\n%d:%d-%d", n.Word(), start, firstEnd, lastEnd)
+		fmt.Fprintf(bw, "\tn%d [label=%q];\n", i, label)
+	}
+	for i, n := range nodes {
+		for _, k := range n.Exits() {
+			_, dst := k.Nodes()
+			start, end := k.Times()
+			prob, ascr := k.Prob()
+			label := fmt.Sprintf("%s\n%d-%d\npost=%d ascr=%d", k.Word(), start, end, prob, ascr)
+			fmt.Fprintf(bw, "\tn%d -> n%d [label=%q];\n", i, ids[dst.node], label)
+		}
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+//Nodes gets all nodes of the lattice.
+func (l *Lattice) Nodes() []LatNode {
+	if l.freed() {
+		return nil
+	}
+	var ret []LatNode
+	for it := C.ps_latnode_iter(l.dag); it != nil; it = C.ps_latnode_iter_next(it) {
+		ret = append(ret, LatNode{lat: l, node: C.ps_latnode_iter_node(it)})
+	}
+	return ret
+}
+
+//Word gets the word of the node, including any alternate pronunciation marker.
+func (n LatNode) Word() string {
+	if n.lat.freed() {
+		return ""
+	}
+	return C.GoString(C.ps_latnode_word(n.lat.dag, n.node))
+}
+
+//BaseWord gets the word of the node without alternate pronunciation marker.
+func (n LatNode) BaseWord() string {
+	if n.lat.freed() {
+		return ""
+	}
+	return C.GoString(C.ps_latnode_baseword(n.lat.dag, n.node))
+}
+
+//Times gets the start frame of the node's word, and the first and last frames in which it may end.
+func (n LatNode) Times() (start, firstEnd, lastEnd int) {
+	if n.lat.freed() {
+		return 0, 0, 0
+	}
+	var fef, lef C.int16
+	sf := C.ps_latnode_times(n.node, &fef, &lef)
+	return int(sf), int(fef), int(lef)
+}
+
+//Prob gets the log posterior probability of the node, along with its best exit link. Posteriors must have been computed by bestpath search.
+func (n LatNode) Prob() (int64, LatLink) {
+	if n.lat.freed() {
+		return 0, LatLink{}
+	}
+	var link *C.ps_latlink_t
+	prob := C.ps_latnode_prob(n.lat.dag, n.node, &link)
+	return int64(prob), LatLink{lat: n.lat, link: link}
+}
+
+//Exits gets the links leaving the node.
+func (n LatNode) Exits() []LatLink {
+	if n.lat.freed() {
+		return nil
+	}
+	return n.lat.links(C.ps_latnode_exits(n.node))
+}
+
+//Entries gets the links entering the node.
+func (n LatNode) Entries() []LatLink {
+	if n.lat.freed() {
+		return nil
+	}
+	return n.lat.links(C.ps_latnode_entries(n.node))
+}
+
+func (l *Lattice) links(it *C.ps_latlink_iter_t) []LatLink {
+	var ret []LatLink
+	for ; it != nil; it = C.ps_latlink_iter_next(it) {
+		ret = append(ret, LatLink{lat: l, link: C.ps_latlink_iter_link(it)})
+	}
+	return ret
+}
+
+//Valid reports whether the link exists. Pred and LatNode.Prob return invalid links when there are none, and links of a freed lattice are invalid.
+func (k LatLink) Valid() bool {
+	return k.link != nil && !k.lat.freed()
+}
+
+//Word gets the word of the link, including any alternate pronunciation marker.
+func (k LatLink) Word() string {
+	if !k.Valid() {
+		return ""
+	}
+	return C.GoString(C.ps_latlink_word(k.lat.dag, k.link))
+}
+
+//BaseWord gets the word of the link without alternate pronunciation marker.
+func (k LatLink) BaseWord() string {
+	if !k.Valid() {
+		return ""
+	}
+	return C.GoString(C.ps_latlink_baseword(k.lat.dag, k.link))
+}
+
+//Times gets the start and end frames of the link.
+func (k LatLink) Times() (start, end int) {
+	if !k.Valid() {
+		return 0, 0
+	}
+	var sf C.int16
+	ef := C.ps_latlink_times(k.link, &sf)
+	return int(sf), int(ef)
+}
+
+//Nodes gets the source and destination nodes of the link.
+func (k LatLink) Nodes() (src, dst LatNode) {
+	if !k.Valid() {
+		return LatNode{}, LatNode{}
+	}
+	var srcNode *C.ps_latnode_t
+	dstNode := C.ps_latlink_nodes(k.link, &srcNode)
+	return LatNode{lat: k.lat, node: srcNode}, LatNode{lat: k.lat, node: dstNode}
+}
+
+//Pred gets the best predecessor of the link, as chosen by bestpath search.
+func (k LatLink) Pred() LatLink {
+	if !k.Valid() {
+		return LatLink{}
+	}
+	return LatLink{lat: k.lat, link: C.ps_latlink_pred(k.link)}
+}
+
+//Prob gets the log posterior probability and the acoustic score of the link.
+func (k LatLink) Prob() (prob, ascr int64) {
+	if !k.Valid() {
+		return 0, 0
+	}
+	var cascr C.int32
+	cprob := C.ps_latlink_prob(k.lat.dag, k.link, &cascr)
+	return int64(cprob), int64(cascr)
+}
+
+//Posterior gets the posterior probability of the link as a number between 0 and 1. Posteriors must have been computed by Lattice.Posterior or bestpath search.
+func (k LatLink) Posterior() float64 {
+	prob, _ := k.Prob()
+	return k.lat.LogMath().Exp(prob)
+}
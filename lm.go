@@ -0,0 +1,171 @@
+package pocketsphinx
+
+/*
+#include <pocketsphinx.h>
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unsafe"
+)
+
+//NgramModel is an n-gram language model, to be added as a search with SetLM. It must be released with Free.
+type NgramModel struct {
+	lm      *C.ngram_model_t
+	setSize int
+}
+
+//LoadLM reads the n-gram language model in the ARPA or binary DMP file at path, using the decoder's configuration and log domain, so it can be added to it with SetLM.
+func (p *PocketSphinx) LoadLM(path string) (*NgramModel, error) {
+	if err := p.acquire(); err != nil {
+		return nil, err
+	}
+	defer p.release()
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	cpath := C.CString(path)
+	defer C.free(unsafe.Pointer(cpath))
+	var lm *C.ngram_model_t
+	reason := captureErrors(func() {
+		lm = C.ngram_model_read(C.ps_get_config(p.ps), cpath, C.NGRAM_AUTO, C.ps_get_logmath(p.ps))
+	})
+	if lm == nil {
+		return nil, searchError("lm", path, ErrBadLM, reason)
+	}
+	return &NgramModel{lm: lm}, nil
+}
+
+//Free releases the model. Searches it was added to keep their own reference.
+func (m *NgramModel) Free() {
+	if m.lm != nil {
+		C.ngram_model_free(m.lm)
+		m.lm = nil
+	}
+}
+
+//Order gets the order of the model, e.g. 3 for a trigram model, or 0 once it is freed.
+func (m *NgramModel) Order() int {
+	if m.lm == nil {
+		return 0
+	}
+	return int(C.ngram_model_get_size(m.lm))
+}
+
+//SetLMFile adds an n-gram search named name for the ARPA or binary DMP language model in the file at path. Use SetSearch to switch to it.
+func (p *PocketSphinx) SetLMFile(name, path string) error {
+	if err := p.acquire(); err != nil {
+		return err
+	}
+	defer p.release()
+	if _, err := os.Stat(path); err != nil {
+		return err
+	}
+	cname := C.CString(name)
+	cpath := C.CString(path)
+	defer C.free(unsafe.Pointer(cname))
+	defer C.free(unsafe.Pointer(cpath))
+	var ret C.int
+	reason := captureErrors(func() {
+		ret = C.ps_set_lm_file(p.ps, cname, cpath)
+	})
+	if ret != 0 {
+		return searchError("lm", name, ErrBadLM, reason)
+	}
+	return nil
+}
+
+//SetLM adds an n-gram search named name for lm, which may be shared between several searches and decoders created with the same configuration. Use SetSearch to switch to it.
+func (p *PocketSphinx) SetLM(name string, lm *NgramModel) error {
+	if err := p.acquire(); err != nil {
+		return err
+	}
+	defer p.release()
+	if lm == nil || lm.lm == nil {
+		return fmt.Errorf("lm %s error:%w:model freed", name, ErrBadLM)
+	}
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	var ret C.int
+	reason := captureErrors(func() {
+		ret = C.ps_set_lm(p.ps, cname, lm.lm)
+	})
+	if ret != 0 {
+		return searchError("lm", name, ErrBadLM, reason)
+	}
+	return nil
+}
+
+//LogMath gets the log domain of the model's scores and probabilities.
+func (m *NgramModel) LogMath() LogMath {
+	if m.lm == nil {
+		return LogMath{}
+	}
+	return LogMath{lmath: C.ngram_model_get_lmath(m.lm)}
+}
+
+//HasWord reports whether word is in the vocabulary of the model. A freed model has none.
+func (m *NgramModel) HasWord(word string) bool {
+	if m.lm == nil {
+		return false
+	}
+	return m.wid(word) != C.ngram_unknown_wid(m.lm)
+}
+
+//Score gets the language score of word following the words of history, oldest first, as used in search: the log probability scaled by the language weight and including the word insertion penalty. It also returns the order of the n-gram the score came from, lower than len(history)+1 if the model backed off.
+func (m *NgramModel) Score(history []string, word string) (score int64, order int) {
+	return m.lookup(history, word, true)
+}
+
+//Prob gets the log probability of word following the words of history, oldest first, as stored in the model. It also returns the order of the n-gram it came from, as Score does.
+func (m *NgramModel) Prob(history []string, word string) (prob int64, order int) {
+	return m.lookup(history, word, false)
+}
+
+//PhraseProb gets the log probability of the words of phrase as a whole sentence, between <s> and </s>. It also returns the words missing from the vocabulary, which get the probability of the unknown word. A freed model returns 0 and no words.
+func (m *NgramModel) PhraseProb(phrase string) (prob int64, oov []string) {
+	if m.lm == nil {
+		return 0, nil
+	}
+	words := strings.Fields(phrase)
+	history := []string{"<s>"}
+	for _, word := range append(words, "</s>") {
+		if !m.HasWord(word) {
+			oov = append(oov, word)
+		}
+		p, _ := m.Prob(history, word)
+		prob += p
+		history = append(history, word)
+	}
+	return prob, oov
+}
+
+//lookup gets the score or probability of word following history, or 0 once the model is freed, like the zero LogMath.
+func (m *NgramModel) lookup(history []string, word string, scaled bool) (int64, int) {
+	if m.lm == nil {
+		return 0, 0
+	}
+	//sphinx wants the most recent word of the history first.
+	hist := make([]C.int32, len(history)+1)
+	for i, w := range history {
+		hist[len(history)-1-i] = m.wid(w)
+	}
+	var nUsed C.int32
+	var ret C.int32
+	if scaled {
+		ret = C.ngram_ng_score(m.lm, m.wid(word), &hist[0], C.int32(len(history)), &nUsed)
+	} else {
+		ret = C.ngram_ng_prob(m.lm, m.wid(word), &hist[0], C.int32(len(history)), &nUsed)
+	}
+	return int64(ret), int(nUsed)
+}
+
+func (m *NgramModel) wid(word string) C.int32 {
+	cword := C.CString(word)
+	defer C.free(unsafe.Pointer(cword))
+	return C.ngram_wid(m.lm, cword)
+}
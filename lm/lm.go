@@ -0,0 +1,205 @@
+//Package lm builds n-gram language models from lists of sentences, so that applications can generate a model of the phrases they expect at startup instead of shipping ARPA files.
+//
+//	m, err := lm.Build([]string{"turn the lights on", "turn the lights off"}, lm.Options{})
+//	err = m.Register(decoder, "commands")
+//
+//Probabilities are estimated with absolute discounting, backing off to lower orders for unseen n-grams.
+package lm
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strings"
+)
+
+//Loader adds a named n-gram search from a language model file to a decoder. *pocketsphinx.PocketSphinx implements it.
+type Loader interface {
+	SetLMFile(name, path string) error
+}
+
+//Options are the parameters of Build.
+type Options struct {
+	//Order is the order of the model, 3 if zero.
+	Order int
+	//Discount is subtracted from the count of every n-gram of order two or higher and given to lower orders, 0.5 if zero. It must be between 0 and 1.
+	Discount float64
+}
+
+//Model is an n-gram language model with backoff.
+type Model struct {
+	order int
+	probs []map[string]float64
+	bows  []map[string]float64
+}
+
+const (
+	sentenceStart = "<s>"
+	sentenceEnd   = "</s>"
+)
+
+//Build estimates a model from sentences. Words are separated by white space and must match the pronunciation dictionary of the decoder the model is used with.
+func Build(sentences []string, opts Options) (*Model, error) {
+	if opts.Order == 0 {
+		opts.Order = 3
+	}
+	if opts.Discount == 0 {
+		opts.Discount = 0.5
+	}
+	if opts.Order < 1 {
+		return nil, fmt.Errorf("lm error:invalid order %d", opts.Order)
+	}
+	if opts.Discount <= 0 || opts.Discount >= 1 {
+		return nil, fmt.Errorf("lm error:invalid discount %v", opts.Discount)
+	}
+
+	//counts[n] holds the counts of the n-grams of order n+1, histories[n] the number of n-grams of order n+2 following each n-gram of order n+1.
+	counts := make([]map[string]int, opts.Order)
+	histories := make([]map[string]int, opts.Order)
+	for n := range counts {
+		counts[n] = make(map[string]int)
+		histories[n] = make(map[string]int)
+	}
+	tokens := 0
+	for _, sentence := range sentences {
+		words := strings.Fields(sentence)
+		if len(words) == 0 {
+			continue
+		}
+		words = append(append([]string{sentenceStart}, words...), sentenceEnd)
+		for i := range words {
+			for n := 0; n < opts.Order && n <= i; n++ {
+				counts[n][strings.Join(words[i-n:i+1], " ")]++
+				if n > 0 {
+					histories[n-1][strings.Join(words[i-n:i], " ")]++
+				}
+			}
+			if i > 0 {
+				tokens++
+			}
+		}
+	}
+	if tokens == 0 {
+		return nil, errors.New("lm error:no sentences")
+	}
+
+	m := &Model{
+		order: opts.Order,
+		probs: make([]map[string]float64, opts.Order),
+		bows:  make([]map[string]float64, opts.Order),
+	}
+	for n := range m.probs {
+		m.probs[n] = make(map[string]float64, len(counts[n]))
+		m.bows[n] = make(map[string]float64)
+	}
+	for w, c := range counts[0] {
+		m.probs[0][w] = float64(c) / float64(tokens)
+	}
+	m.probs[0][sentenceStart] = 0
+	for n := 1; n < opts.Order; n++ {
+		for ngram, c := range counts[n] {
+			history := ngram[:strings.LastIndexByte(ngram, ' ')]
+			m.probs[n][ngram] = (float64(c) - opts.Discount) / float64(histories[n-1][history])
+		}
+		//The mass left over for each history is distributed over the words not seen after it, in proportion to their lower order probability.
+		left := make(map[string]float64)
+		lower := make(map[string]float64)
+		for ngram, p := range m.probs[n] {
+			words := strings.Fields(ngram)
+			history := strings.Join(words[:n], " ")
+			left[history] += p
+			lower[history] += m.prob(words[1:n], words[n])
+		}
+		for history, seen := range left {
+			if lower[history] >= 1 {
+				m.bows[n-1][history] = 1
+				continue
+			}
+			m.bows[n-1][history] = (1 - seen) / (1 - lower[history])
+		}
+	}
+	return m, nil
+}
+
+//Order gets the order of the model.
+func (m *Model) Order() int {
+	return m.order
+}
+
+//Prob gets the probability of word following the words of history, backing off to shorter histories if it wasn't seen after it.
+func (m *Model) Prob(history []string, word string) float64 {
+	if len(history) >= m.order {
+		history = history[len(history)-m.order+1:]
+	}
+	return m.prob(history, word)
+}
+
+func (m *Model) prob(history []string, word string) float64 {
+	if len(history) == 0 {
+		return m.probs[0][word]
+	}
+	ngram := strings.Join(append(append([]string(nil), history...), word), " ")
+	if p, ok := m.probs[len(history)][ngram]; ok {
+		return p
+	}
+	bow, ok := m.bows[len(history)-1][strings.Join(history, " ")]
+	if !ok {
+		bow = 1
+	}
+	return bow * m.prob(history[1:], word)
+}
+
+//WriteARPA writes the model to w in ARPA format.
+func (m *Model) WriteARPA(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "\\data\\")
+	for n := range m.probs {
+		fmt.Fprintf(bw, "ngram %d=%d\n", n+1, len(m.probs[n]))
+	}
+	for n := range m.probs {
+		fmt.Fprintf(bw, "\n\\%d-grams:\n", n+1)
+		ngrams := make([]string, 0, len(m.probs[n]))
+		for ngram := range m.probs[n] {
+			ngrams = append(ngrams, ngram)
+		}
+		sort.Strings(ngrams)
+		for _, ngram := range ngrams {
+			fmt.Fprintf(bw, "%.6f %s", log10(m.probs[n][ngram]), ngram)
+			if bow, ok := m.bows[n][ngram]; ok {
+				fmt.Fprintf(bw, " %.6f", log10(bow))
+			}
+			fmt.Fprintln(bw)
+		}
+	}
+	fmt.Fprintln(bw, "\n\\end\\")
+	return bw.Flush()
+}
+
+//Register writes the model to a temporary file and adds it to d as an n-gram search named name.
+func (m *Model) Register(d Loader, name string) error {
+	f, err := os.CreateTemp("", "lm-*.arpa")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if err := m.WriteARPA(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return d.SetLMFile(name, f.Name())
+}
+
+//log10 is math.Log10 with the ARPA convention of -99 for a probability of zero.
+func log10(p float64) float64 {
+	if p <= 0 {
+		return -99
+	}
+	return math.Log10(p)
+}
@@ -0,0 +1,131 @@
+package lm
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestBuildErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		sentences []string
+		opts      Options
+	}{
+		{name: "negative order", sentences: []string{"a"}, opts: Options{Order: -1}},
+		{name: "negative discount", sentences: []string{"a"}, opts: Options{Discount: -0.5}},
+		{name: "discount of one", sentences: []string{"a"}, opts: Options{Discount: 1}},
+		{name: "no sentences"},
+		{name: "blank sentences", sentences: []string{"", "  "}},
+	}
+	for _, tt := range tests {
+		if _, err := Build(tt.sentences, tt.opts); err == nil {
+			t.Errorf("%s: Build succeeded", tt.name)
+		}
+	}
+}
+
+func TestProb(t *testing.T) {
+	m, err := Build([]string{"a b", "a c"}, Options{Order: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	//There are 6 tokens, a and </s> twice each, and bigrams are discounted by 0.5.
+	tests := []struct {
+		history []string
+		word    string
+		want    float64
+	}{
+		{history: nil, word: "a", want: 2.0 / 6},
+		{history: nil, word: "b", want: 1.0 / 6},
+		{history: nil, word: "<s>", want: 0},
+		{history: nil, word: "unknown", want: 0},
+		{history: []string{"<s>"}, word: "a", want: 0.75},
+		{history: []string{"a"}, word: "b", want: 0.25},
+		{history: []string{"b"}, word: "</s>", want: 0.5},
+		//Backed off with the weight 0.25/(1-1/3) of <s> and 0.5/(1-1/3) of a.
+		{history: []string{"<s>"}, word: "b", want: 0.375 / 6},
+		{history: []string{"a"}, word: "</s>", want: 0.75 * 2 / 6},
+		//Histories without a weight back off with 1, and long ones are truncated to the order.
+		{history: []string{"unknown"}, word: "a", want: 2.0 / 6},
+		{history: []string{"x", "y", "a"}, word: "c", want: 0.25},
+	}
+	for _, tt := range tests {
+		if got := m.Prob(tt.history, tt.word); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Prob(%q, %q) got %v, want %v", tt.history, tt.word, got, tt.want)
+		}
+	}
+
+	//The probabilities following a history sum to one.
+	sum := 0.0
+	for _, w := range []string{"a", "b", "c", "</s>"} {
+		sum += m.Prob([]string{"a"}, w)
+	}
+	if math.Abs(sum-1) > 1e-9 {
+		t.Errorf("probabilities after a sum to %v", sum)
+	}
+}
+
+func TestWriteARPA(t *testing.T) {
+	m, err := Build([]string{"a b", "a c"}, Options{Order: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := m.WriteARPA(&buf); err != nil {
+		t.Fatal(err)
+	}
+	text := buf.String()
+	tests := []string{
+		"\\data\\\nngram 1=5\nngram 2=5\n",
+		"\n\\1-grams:\n-0.477121 </s>\n-99.000000 <s> -0.425969\n-0.477121 a -0.124939\n",
+		"\n\\2-grams:\n-0.124939 <s> a\n-0.602060 a b\n-0.602060 a c\n-0.301030 b </s>\n-0.301030 c </s>\n",
+	}
+	for _, want := range tests {
+		if !strings.Contains(text, want) {
+			t.Errorf("ARPA missing\n%s\ngot\n%s", want, text)
+		}
+	}
+	if !strings.HasSuffix(text, "\n\\end\\\n") {
+		t.Errorf("ARPA doesn't end with \\end\\:\n%s", text)
+	}
+}
+
+type fakeLoader struct {
+	name, text string
+	err        error
+}
+
+func (f *fakeLoader) SetLMFile(name, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	f.name, f.text = name, string(data)
+	return f.err
+}
+
+func TestRegister(t *testing.T) {
+	m, err := Build([]string{"turn on", "turn off"}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := m.WriteARPA(&buf); err != nil {
+		t.Fatal(err)
+	}
+	l := &fakeLoader{}
+	if err := m.Register(l, "commands"); err != nil {
+		t.Fatal(err)
+	}
+	if l.name != "commands" || l.text != buf.String() {
+		t.Errorf("registered %q as %q", l.text, l.name)
+	}
+	l = &fakeLoader{err: errors.New("load failed")}
+	if err := m.Register(l, "commands"); err != l.err {
+		t.Errorf("Register error %v, want %v", err, l.err)
+	}
+}
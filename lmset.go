@@ -0,0 +1,134 @@
+package pocketsphinx
+
+/*
+#include <pocketsphinx.h>
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+)
+
+//WeightedLM is a language model of a set, with its name and interpolation weight.
+type WeightedLM struct {
+	Name   string
+	Model  *NgramModel
+	Weight float64
+}
+
+//NewLMSet combines lms into a single model interpolating their probabilities, e.g. a general model and a small model of domain specific commands, which can be added as a search with SetLM. Weights are normalized to sum to 1. The set keeps its own reference to the models, which may be freed.
+func (p *PocketSphinx) NewLMSet(lms ...WeightedLM) (*NgramModel, error) {
+	if err := p.acquire(); err != nil {
+		return nil, err
+	}
+	defer p.release()
+	if len(lms) == 0 {
+		return nil, fmt.Errorf("lm set error:%w:no models", ErrBadLM)
+	}
+	for _, lm := range lms {
+		if lm.Model == nil || lm.Model.lm == nil {
+			return nil, fmt.Errorf("lm %s error:%w:model freed", lm.Name, ErrBadLM)
+		}
+	}
+	weights, err := normalizeWeights(lms)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(lms)
+	models := (*[1 << 20]*C.ngram_model_t)(C.malloc(C.size_t(n) * C.size_t(unsafe.Sizeof((*C.ngram_model_t)(nil)))))[:n:n]
+	defer C.free(unsafe.Pointer(&models[0]))
+	names := cStrings(lms)
+	defer freeCStrings(names)
+	cweights := cWeights(weights)
+	defer C.free(unsafe.Pointer(&cweights[0]))
+	for i, lm := range lms {
+		//The set takes ownership of the models.
+		models[i] = C.ngram_model_retain(lm.Model.lm)
+	}
+
+	var set *C.ngram_model_t
+	reason := captureErrors(func() {
+		set = C.ngram_model_set_init(C.ps_get_config(p.ps), &models[0], &names[0], &cweights[0], C.int32(n))
+	})
+	if set == nil {
+		for _, m := range models {
+			C.ngram_model_free(m)
+		}
+		return nil, searchError("lm", "set", ErrBadLM, reason)
+	}
+	return &NgramModel{lm: set, setSize: n}, nil
+}
+
+//Interpolate changes the interpolation weights of the models of a set created with NewLMSet. Every model must be given a weight, the weights are normalized to sum to 1. Searches using the set use the new weights from the next utterance.
+func (m *NgramModel) Interpolate(lms ...WeightedLM) error {
+	if m.setSize == 0 {
+		return errors.New("lm error:not a set")
+	}
+	if len(lms) != m.setSize {
+		return fmt.Errorf("lm set error:got %d weights for %d models", len(lms), m.setSize)
+	}
+	weights, err := normalizeWeights(lms)
+	if err != nil {
+		return err
+	}
+	names := cStrings(lms)
+	defer freeCStrings(names)
+	cweights := cWeights(weights)
+	defer C.free(unsafe.Pointer(&cweights[0]))
+	var ret *C.ngram_model_t
+	reason := captureErrors(func() {
+		ret = C.ngram_model_set_interp(m.lm, (**C.char)(unsafe.Pointer(&names[0])), &cweights[0])
+	})
+	if ret == nil {
+		return searchError("lm", "set", ErrBadLM, reason)
+	}
+	return nil
+}
+
+func normalizeWeights(lms []WeightedLM) ([]float64, error) {
+	var sum float64
+	for _, lm := range lms {
+		if lm.Name == "" {
+			return nil, errors.New("lm set error:model without name")
+		}
+		if lm.Weight <= 0 {
+			return nil, fmt.Errorf("lm %s error:weight %v must be positive", lm.Name, lm.Weight)
+		}
+		sum += lm.Weight
+	}
+	weights := make([]float64, len(lms))
+	for i, lm := range lms {
+		weights[i] = lm.Weight / sum
+	}
+	return weights, nil
+}
+
+//cStrings copies the names of lms to an array allocated in C, to be released with freeCStrings.
+func cStrings(lms []WeightedLM) []*C.char {
+	n := len(lms)
+	ret := (*[1 << 20]*C.char)(C.malloc(C.size_t(n) * C.size_t(unsafe.Sizeof((*C.char)(nil)))))[:n:n]
+	for i, lm := range lms {
+		ret[i] = C.CString(lm.Name)
+	}
+	return ret
+}
+
+func freeCStrings(strs []*C.char) {
+	for _, s := range strs {
+		C.free(unsafe.Pointer(s))
+	}
+	C.free(unsafe.Pointer(&strs[0]))
+}
+
+func cWeights(weights []float64) []C.float32 {
+	n := len(weights)
+	ret := (*[1 << 20]C.float32)(C.malloc(C.size_t(n) * C.size_t(unsafe.Sizeof(C.float32(0)))))[:n:n]
+	for i, w := range weights {
+		ret[i] = C.float32(w)
+	}
+	return ret
+}
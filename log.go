@@ -0,0 +1,359 @@
+package pocketsphinx
+
+/*
+#include <pocketsphinx.h>
+#include <err.h>
+#include <stdarg.h>
+#include <stdint.h>
+#include <stdio.h>
+#include <stdlib.h>
+#include <string.h>
+extern void goSphinxLog(uintptr_t owner, int level, char *msg);
+static __thread uintptr_t log_owner;
+static __thread char log_line[1024];
+static __thread size_t log_len;
+static __thread int log_lvl;
+static __thread int capture_active;
+static __thread char capture_buf[512];
+//capture_begin starts recording the errors logged by the calling thread, for capture_end to return the last one.
+void capture_begin(){
+    capture_active = 1;
+    capture_buf[0] = '\0';
+}
+void capture_end(char *errbuf, size_t errlen){
+    capture_active = 0;
+    snprintf(errbuf, errlen, "%s", capture_buf);
+}
+static void set_log_owner(uintptr_t owner){
+    log_owner = owner;
+}
+static void log_append(const char *s, size_t n){
+    if (n > sizeof(log_line) - 1 - log_len)
+        n = sizeof(log_line) - 1 - log_len;
+    memcpy(log_line + log_len, s, n);
+    log_len += n;
+    log_line[log_len] = '\0';
+}
+#ifdef POCKETSPHINX5
+static void log_callback(void *user_data, err_lvl_t lvl, const char *msg){
+    const char *p, *nl;
+    FILE *fp;
+#else
+static void log_callback(void *user_data, err_lvl_t lvl, const char *fmt, ...){
+    char msg[1024];
+    char *p, *nl;
+    va_list ap;
+    FILE *fp;
+    va_start(ap, fmt);
+    vsnprintf(msg, sizeof(msg), fmt, ap);
+    va_end(ap);
+#endif
+    fp = err_get_logfp();
+    if (fp != NULL) {
+        fputs(msg, fp);
+        fflush(fp);
+    }
+    if (capture_active && (lvl == ERR_ERROR || lvl == ERR_FATAL)) {
+#ifdef POCKETSPHINX5
+        // pocketsphinx 5 passes messages without their level prefix, which legacy messages start with.
+        snprintf(capture_buf, sizeof(capture_buf), "%s: %s", lvl == ERR_ERROR ? "ERROR" : "FATAL", msg);
+#else
+        snprintf(capture_buf, sizeof(capture_buf), "%s", msg);
+#endif
+    }
+    if (log_len == 0)
+        log_lvl = lvl;
+    for (p = msg; (nl = strchr(p, '\n')) != NULL; p = nl + 1) {
+        log_append(p, nl - p);
+        goSphinxLog(log_owner, log_lvl, log_line);
+        log_len = 0;
+        log_lvl = lvl;
+    }
+    log_append(p, strlen(p));
+}
+static void install_log_callback(){
+    err_set_logfp(NULL);
+    err_set_callback(log_callback, NULL);
+}
+*/
+import "C"
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+//LogLevel is the severity of a message logged by pocketsphinx.
+type LogLevel int
+
+const (
+	LogDebug LogLevel = iota
+	LogInfo
+	LogWarn
+	LogError
+	LogFatal
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case LogDebug:
+		return "DEBUG"
+	case LogInfo:
+		return "INFO"
+	case LogWarn:
+		return "WARN"
+	case LogError:
+		return "ERROR"
+	case LogFatal:
+		return "FATAL"
+	}
+	return "UNKNOWN"
+}
+
+//LogHandler receives the messages logged by pocketsphinx, one line at a time, without their level prefix.
+type LogHandler func(level LogLevel, msg string)
+
+//SlogHandler gets a LogHandler logging to l. Fatal messages are logged above the error level.
+func SlogHandler(l *slog.Logger) LogHandler {
+	return func(level LogLevel, msg string) {
+		lvl := slog.LevelInfo
+		switch level {
+		case LogDebug:
+			lvl = slog.LevelDebug
+		case LogWarn:
+			lvl = slog.LevelWarn
+		case LogError:
+			lvl = slog.LevelError
+		case LogFatal:
+			lvl = slog.LevelError + 4
+		}
+		l.Log(context.Background(), lvl, msg)
+	}
+}
+
+type logHolder struct {
+	h LogHandler
+}
+
+var logHandler atomic.Value
+
+//decoderLog is the logging set up for a decoder. The zero value forwards all messages to the package level handler.
+type decoderLog struct {
+	h     LogHandler
+	file  *os.File
+	level LogLevel
+}
+
+var (
+	logMu     sync.Mutex
+	logOwners = make(map[uintptr]*decoderLog)
+	lastLogID uintptr
+)
+
+func init() {
+	//sphinx logs to stderr by default; route everything through the bridge instead, which discards messages until a handler is set.
+	C.install_log_callback()
+}
+
+//SetLogHandler forwards the messages pocketsphinx logs to h, replacing the previous handler, except those of decoders with their own handler. A nil h discards them, which is the default.
+func SetLogHandler(h LogHandler) {
+	logHandler.Store(logHolder{h: h})
+}
+
+//SetLogger forwards the messages pocketsphinx logs to l, as SetLogHandler does. A nil l discards them.
+func SetLogger(l *slog.Logger) {
+	if l == nil {
+		SetLogHandler(nil)
+		return
+	}
+	SetLogHandler(SlogHandler(l))
+}
+
+//SetLogHandler forwards the messages logged while p is being used to h rather than to the handler set with the package level SetLogHandler, so the diagnostics of decoders running concurrently can be told apart. Messages logged while a decoder is created go to the package level handler. A nil h reverts to it.
+func (p *PocketSphinx) SetLogHandler(h LogHandler) error {
+	if err := p.acquire(); err != nil {
+		return err
+	}
+	defer p.release()
+	p.setLogHandler(h)
+	return nil
+}
+
+//SetLogger forwards the messages logged while p is being used to l, as SetLogHandler does. A nil l reverts to the package level handler.
+func (p *PocketSphinx) SetLogger(l *slog.Logger) error {
+	if l == nil {
+		return p.SetLogHandler(nil)
+	}
+	return p.SetLogHandler(SlogHandler(l))
+}
+
+//SetLogLevel sets the lowest level of the messages logged while p is being used that are forwarded to its handler or log file, or to the package level handler. All are by default; debug messages are only logged at all if enabled with the -debug flag.
+func (p *PocketSphinx) SetLogLevel(level LogLevel) error {
+	if err := p.acquire(); err != nil {
+		return err
+	}
+	defer p.release()
+	p.updateLog(func(l *decoderLog) {
+		l.level = level
+	})
+	return nil
+}
+
+//SetLogFile appends the messages logged while p is being used to the file at path, replacing its handler. An empty path stops logging to the file and reverts to the package level handler, while os.DevNull, or /dev/null on any platform, discards the messages of p. Setting the -logfn flag has the same effect, rather than changing where all decoders log.
+func (p *PocketSphinx) SetLogFile(path string) error {
+	if err := p.acquire(); err != nil {
+		return err
+	}
+	defer p.release()
+	return p.setLogFile(path)
+}
+
+func (p *PocketSphinx) setLogFile(path string) error {
+	var f *os.File
+	if path != "" && !discardPath(path) {
+		var err error
+		f, err = os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+		if err != nil {
+			return fmt.Errorf("logfn error:%v", err)
+		}
+	}
+	p.updateLog(func(l *decoderLog) {
+		l.h = nil
+		if f != nil {
+			l.h = func(level LogLevel, msg string) {
+				fmt.Fprintf(f, "%s: %s\n", level, msg)
+			}
+		} else if discardPath(path) {
+			l.h = func(LogLevel, string) {}
+		}
+		l.setFile(f)
+	})
+	return nil
+}
+
+//discardPath reports whether path is the null device, accepting /dev/null on every platform so configurations written for unix work on Windows.
+func discardPath(path string) bool {
+	return path == os.DevNull || path == "/dev/null"
+}
+
+func (p *PocketSphinx) setLogHandler(h LogHandler) {
+	p.updateLog(func(l *decoderLog) {
+		l.h = h
+		l.setFile(nil)
+	})
+}
+
+//resetLog reverts p to the default logging, closing its log file.
+func (p *PocketSphinx) resetLog() {
+	p.updateLog(func(l *decoderLog) {
+		l.setFile(nil)
+		*l = decoderLog{}
+	})
+}
+
+//setFile closes the log file replaced by f.
+func (l *decoderLog) setFile(f *os.File) {
+	if l.file != nil {
+		l.file.Close()
+	}
+	l.file = f
+}
+
+//updateLog changes the logging of p with update, registering p with the log bridge as long as its logging differs from the default.
+func (p *PocketSphinx) updateLog(update func(l *decoderLog)) {
+	logMu.Lock()
+	defer logMu.Unlock()
+	l := logOwners[p.logID]
+	if l == nil {
+		l = &decoderLog{}
+	}
+	update(l)
+	if l.h == nil && l.file == nil && l.level == LogDebug {
+		delete(logOwners, p.logID)
+		p.logID = 0
+		return
+	}
+	if p.logID == 0 {
+		lastLogID++
+		p.logID = lastLogID
+		logOwners[p.logID] = l
+	}
+}
+
+//takeLogFile clears the -logfn flag of psConfig, which would make sphinx log all decoders to the file, and returns its value.
+func takeLogFile(psConfig *C.cmd_ln_t) string {
+	path := getStringParam(psConfig, "-logfn")
+	if path != "" {
+		key := C.CString("-logfn")
+		defer C.free(unsafe.Pointer(key))
+		C.cmd_ln_set_str_r(psConfig, key, nil)
+	}
+	return path
+}
+
+//logEnter routes the messages logged on the current thread to p's handler, if it has one, until logExit. The goroutine is locked to its thread meanwhile, as sphinx doesn't tell which decoder logs a message.
+func (p *PocketSphinx) logEnter() {
+	if p.logID == 0 {
+		return
+	}
+	runtime.LockOSThread()
+	C.set_log_owner(C.uintptr_t(p.logID))
+	p.logLocked = true
+}
+
+func (p *PocketSphinx) logExit() {
+	if !p.logLocked {
+		return
+	}
+	C.set_log_owner(0)
+	p.logLocked = false
+	runtime.UnlockOSThread()
+}
+
+//dispatchLog passes a line logged by sphinx to the handler of owner, or the package level one.
+func dispatchLog(owner uintptr, level C.int, msg string) {
+	var lvl LogLevel
+	switch level {
+	case C.ERR_DEBUG:
+		lvl = LogDebug
+	case C.ERR_INFO, C.ERR_INFOCONT:
+		lvl = LogInfo
+	case C.ERR_WARN:
+		lvl = LogWarn
+	case C.ERR_ERROR:
+		lvl = LogError
+	default:
+		lvl = LogFatal
+	}
+	var h LogHandler
+	if owner != 0 {
+		logMu.Lock()
+		if l := logOwners[owner]; l != nil {
+			if lvl < l.level {
+				logMu.Unlock()
+				return
+			}
+			h = l.h
+		}
+		logMu.Unlock()
+	}
+	if h == nil {
+		holder, _ := logHandler.Load().(logHolder)
+		h = holder.h
+	}
+	if h == nil {
+		return
+	}
+	msg = strings.TrimSpace(strings.TrimPrefix(msg, lvl.String()+":"))
+	if msg == "" {
+		return
+	}
+	h(lvl, msg)
+}
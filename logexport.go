@@ -0,0 +1,13 @@
+package pocketsphinx
+
+//Files exporting Go functions to C may only declare C functions, so the log callback calling this one is in log.go.
+
+/*
+#include <stdint.h>
+*/
+import "C"
+
+//export goSphinxLog
+func goSphinxLog(owner C.uintptr_t, level C.int, msg *C.char) {
+	dispatchLog(uintptr(owner), level, C.GoString(msg))
+}
@@ -0,0 +1,97 @@
+package pocketsphinx
+
+/*
+#include <pocketsphinx.h>
+*/
+import "C"
+
+//LogMath converts the integer log domain scores and probabilities returned by the decoder. It is only valid as long as the decoder or lattice it came from.
+//The zero LogMath converts everything to zero.
+type LogMath struct {
+	lmath *C.logmath_t
+}
+
+//LogMath gets the log domain used by the decoder. That of a freed decoder, or one in use by another goroutine, is the zero LogMath.
+func (p *PocketSphinx) LogMath() LogMath {
+	if p.acquire() != nil {
+		return LogMath{}
+	}
+	defer p.release()
+	return p.logMath()
+}
+
+func (p *PocketSphinx) logMath() LogMath {
+	return LogMath{lmath: C.ps_get_logmath(p.ps)}
+}
+
+//LogMath gets the log domain used by the lattice.
+func (l *Lattice) LogMath() LogMath {
+	if l.freed() {
+		return LogMath{}
+	}
+	return LogMath{lmath: C.ps_lattice_get_logmath(l.dag)}
+}
+
+//Base gets the base of the logarithm.
+func (m LogMath) Base() float64 {
+	if m.lmath == nil {
+		return 0
+	}
+	return float64(C.logmath_get_base(m.lmath))
+}
+
+//Zero gets the value representing a probability of zero.
+func (m LogMath) Zero() int64 {
+	if m.lmath == nil {
+		return 0
+	}
+	return int64(C.logmath_get_zero(m.lmath))
+}
+
+//Exp converts logp to a linear probability.
+func (m LogMath) Exp(logp int64) float64 {
+	if m.lmath == nil {
+		return 0
+	}
+	return float64(C.logmath_exp(m.lmath, C.int(logp)))
+}
+
+//Log converts the linear probability p to the log domain.
+func (m LogMath) Log(p float64) int64 {
+	if m.lmath == nil {
+		return 0
+	}
+	return int64(C.logmath_log(m.lmath, C.float64(p)))
+}
+
+//LogToLn converts logp to a natural logarithm.
+func (m LogMath) LogToLn(logp int64) float64 {
+	if m.lmath == nil {
+		return 0
+	}
+	return float64(C.logmath_log_to_ln(m.lmath, C.int(logp)))
+}
+
+//LnToLog converts the natural logarithm lnp to the log domain.
+func (m LogMath) LnToLog(lnp float64) int64 {
+	if m.lmath == nil {
+		return 0
+	}
+	return int64(C.logmath_ln_to_log(m.lmath, C.float64(lnp)))
+}
+
+//LogToLog10 converts logp to a base 10 logarithm.
+func (m LogMath) LogToLog10(logp int64) float64 {
+	if m.lmath == nil {
+		return 0
+	}
+	return float64(C.logmath_log_to_log10(m.lmath, C.int(logp)))
+}
+
+//Add adds two probabilities in the log domain, returning log(exp(logp) + exp(logq)).
+func (m LogMath) Add(logp, logq int64) int64 {
+	if m.lmath == nil {
+		return 0
+	}
+	return int64(C.logmath_add(m.lmath, C.int(logp), C.int(logq)))
+}
@@ -0,0 +1,160 @@
+package pocketsphinx
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+//ErrEmptySearchStack is returned by PopSearch when no search was pushed.
+var ErrEmptySearchStack = errors.New("no pushed search")
+
+//SearchManager owns a set of named searches of a decoder, and switches between them, remembering the previously active search. PushSearch and PopSearch switch to a search temporarily, e.g. to a yes/no grammar to confirm a command. Like the decoder, it must not be used concurrently, and searches can only be switched between utterances.
+type SearchManager struct {
+	p        *PocketSphinx
+	owned    map[string]bool
+	previous string
+	stack    []string
+}
+
+//NewSearchManager creates SearchManager for p.
+func NewSearchManager(p *PocketSphinx) *SearchManager {
+	return &SearchManager{p: p, owned: make(map[string]bool)}
+}
+
+//AddJSGF adds a search for the JSGF grammar, as ParseJSGF does.
+func (m *SearchManager) AddJSGF(name, grammar string) error {
+	return m.add(name, m.p.ParseJSGF(name, grammar))
+}
+
+//AddJSGFFile adds a search for the JSGF grammar file, as SetJSGFFile does.
+func (m *SearchManager) AddJSGFFile(name, path string) error {
+	return m.add(name, m.p.SetJSGFFile(name, path))
+}
+
+//AddKeyphrase adds a keyword search for keyphrase, as SetKeyphrase does.
+func (m *SearchManager) AddKeyphrase(name, keyphrase string) error {
+	return m.add(name, m.p.SetKeyphrase(name, keyphrase))
+}
+
+//AddKeywords adds a keyword search for kws, as SetKeywordList does.
+func (m *SearchManager) AddKeywords(name string, kws []Keyword) error {
+	return m.add(name, m.p.SetKeywordList(name, kws))
+}
+
+//AddLMFile adds an n-gram search for the language model file, as SetLMFile does.
+func (m *SearchManager) AddLMFile(name, path string) error {
+	return m.add(name, m.p.SetLMFile(name, path))
+}
+
+//AddLM adds an n-gram search for lm, as SetLM does.
+func (m *SearchManager) AddLM(name string, lm *NgramModel) error {
+	return m.add(name, m.p.SetLM(name, lm))
+}
+
+func (m *SearchManager) add(name string, err error) error {
+	if err != nil {
+		return err
+	}
+	m.owned[name] = true
+	return nil
+}
+
+//Names gets the names of the searches added through the manager, in sorted order.
+func (m *SearchManager) Names() []string {
+	ret := make([]string, 0, len(m.owned))
+	for name := range m.owned {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+//Remove removes a search added through the manager. Active and pushed searches can't be removed.
+func (m *SearchManager) Remove(name string) error {
+	if !m.owned[name] {
+		return fmt.Errorf("search %s error:%w", name, ErrUnknownSearch)
+	}
+	for _, pushed := range m.stack {
+		if pushed == name {
+			return fmt.Errorf("search %s error:search is pushed", name)
+		}
+	}
+	if err := m.p.UnsetSearch(name); err != nil {
+		return err
+	}
+	delete(m.owned, name)
+	if m.previous == name {
+		m.previous = ""
+	}
+	return nil
+}
+
+//Active gets the name of the active search.
+func (m *SearchManager) Active() string {
+	return m.p.GetSearch()
+}
+
+//Previous gets the name of the search that was active before the last switch, or an empty string.
+func (m *SearchManager) Previous() string {
+	return m.previous
+}
+
+//Switch activates the search named name, which may also be one not added through the manager.
+func (m *SearchManager) Switch(name string) error {
+	current := m.p.GetSearch()
+	if err := m.p.SetSearch(name); err != nil {
+		return err
+	}
+	if current != name {
+		m.previous = current
+	}
+	return nil
+}
+
+//SwitchBack activates the previously active search.
+func (m *SearchManager) SwitchBack() error {
+	if m.previous == "" {
+		return errors.New("no previous search")
+	}
+	return m.Switch(m.previous)
+}
+
+//PushSearch activates the search named name, remembering the active one so PopSearch can restore it.
+func (m *SearchManager) PushSearch(name string) error {
+	current := m.p.GetSearch()
+	if err := m.Switch(name); err != nil {
+		return err
+	}
+	m.stack = append(m.stack, current)
+	return nil
+}
+
+//PopSearch restores the search that was active before the last PushSearch, and returns its name.
+func (m *SearchManager) PopSearch() (string, error) {
+	if len(m.stack) == 0 {
+		return "", ErrEmptySearchStack
+	}
+	name := m.stack[len(m.stack)-1]
+	if err := m.Switch(name); err != nil {
+		return "", err
+	}
+	m.stack = m.stack[:len(m.stack)-1]
+	return name, nil
+}
+
+//Close removes all searches added through the manager except the active one.
+func (m *SearchManager) Close() error {
+	m.stack = nil
+	active := m.p.GetSearch()
+	var firstErr error
+	for _, name := range m.Names() {
+		if name == active {
+			continue
+		}
+		if err := m.Remove(name); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,86 @@
+package pocketsphinx
+
+/*
+#include <pocketsphinx.h>
+*/
+import "C"
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//ModelMemory is an estimate of the memory used by the models of a decoder, in bytes, based on the size of their files. Mapped is the part of the total that is memory mapped (-mmap) rather than read, which the kernel loads on demand and shares between processes.
+type ModelMemory struct {
+	Acoustic int64 `json:"acoustic"`
+	Dict     int64 `json:"dict"`
+	LM       int64 `json:"lm"`
+	Mapped   int64 `json:"mapped"`
+}
+
+//Total gets the estimated memory of all models.
+func (m ModelMemory) Total() int64 {
+	return m.Acoustic + m.Dict + m.LM
+}
+
+//Resident gets the estimated memory of the models that is read rather than mapped.
+func (m ModelMemory) Resident() int64 {
+	return m.Total() - m.Mapped
+}
+
+//ModelMemory estimates the memory used by the models the decoder was configured with, to help size deployments on small boards. Searches added at runtime aren't counted.
+func (p *PocketSphinx) ModelMemory() (ModelMemory, error) {
+	if err := p.acquire(); err != nil {
+		return ModelMemory{}, err
+	}
+	defer p.release()
+	psConfig := C.ps_get_config(p.ps)
+	mmap := getIntParam(psConfig, "-mmap") != 0
+
+	var m ModelMemory
+	if hmm := getStringParam(psConfig, "-hmm"); hmm != "" {
+		entries, err := os.ReadDir(hmm)
+		if err != nil {
+			return m, err
+		}
+		for _, e := range entries {
+			info, err := e.Info()
+			if err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+			m.Acoustic += info.Size()
+			if mmap && e.Name() == "sendump" {
+				m.Mapped += info.Size()
+			}
+		}
+	}
+	for _, key := range []string{"-dict", "-fdict"} {
+		m.Dict += fileSize(getStringParam(psConfig, key))
+	}
+	if lm := getStringParam(psConfig, "-lm"); lm != "" {
+		size := fileSize(lm)
+		m.LM += size
+		if mmap && isBinaryLM(lm) {
+			m.Mapped += size
+		}
+	}
+	return m, nil
+}
+
+func fileSize(path string) int64 {
+	if path == "" {
+		return 0
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+//isBinaryLM reports whether path names a binary language model, which sphinx can memory map, rather than an ARPA text one.
+func isBinaryLM(path string) bool {
+	name := strings.ToLower(filepath.Base(path))
+	return strings.HasSuffix(name, ".bin") || strings.HasSuffix(name, ".dmp")
+}
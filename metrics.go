@@ -0,0 +1,38 @@
+package pocketsphinx
+
+import (
+	"sync/atomic"
+)
+
+//Metrics receives measurements of all decoders and pools of the package, e.g. to export them to a monitoring system. Methods are called on the goroutines using the decoders, so implementations must be safe for concurrent use and fast. See the prommetrics package for a Prometheus implementation.
+type Metrics interface {
+	//UtteranceDecoded is called when an utterance ends, with its timing.
+	UtteranceDecoded(t Timing)
+	//DecodeError is called when a decoder operation fails in sphinx, with the name of the operation, e.g. "process_raw".
+	DecodeError(op string)
+	//PoolChanged is called with the state of a Pool after decoders are taken from it or returned to it.
+	PoolChanged(s PoolStats)
+}
+
+type metricsHolder struct {
+	m Metrics
+}
+
+var metrics atomic.Value
+
+//SetMetrics registers m to receive measurements, replacing the previous one. A nil m disables them.
+func SetMetrics(m Metrics) {
+	metrics.Store(metricsHolder{m: m})
+}
+
+func getMetrics() Metrics {
+	h, _ := metrics.Load().(metricsHolder)
+	return h.m
+}
+
+//decodeError reports a failure of op to the registered Metrics.
+func decodeError(op string) {
+	if m := getMetrics(); m != nil {
+		m.DecodeError(op)
+	}
+}
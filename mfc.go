@@ -0,0 +1,91 @@
+package pocketsphinx
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+)
+
+//ReadMFC reads a sphinx feature file (.mfc), as written by sphinx_fe and the SphinxTrain tools, from r. The file only records the total number of coefficients, so veclen is the number of coefficients of a frame, 13 for most models. The byte order is detected from the header, as sphinx does.
+func ReadMFC(r io.Reader, veclen int) ([][]float32, error) {
+	if veclen <= 0 {
+		return nil, fmt.Errorf("mfc error:invalid vector length %d", veclen)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("mfc error:%v", err)
+	}
+	if len(data) < 4 {
+		return nil, errors.New("mfc error:short header")
+	}
+	n := len(data)/4 - 1
+	var order binary.ByteOrder = binary.BigEndian
+	if int(order.Uint32(data)) != n {
+		order = binary.LittleEndian
+		if int(order.Uint32(data)) != n {
+			return nil, fmt.Errorf("mfc error:header doesn't match file size %d", len(data))
+		}
+	}
+	if n%veclen != 0 {
+		return nil, fmt.Errorf("mfc error:%d coefficients isn't a multiple of vector length %d", n, veclen)
+	}
+	coefs := make([]float32, n)
+	for i := range coefs {
+		coefs[i] = math.Float32frombits(order.Uint32(data[4+i*4:]))
+	}
+	ret := make([][]float32, n/veclen)
+	for i := range ret {
+		ret[i] = coefs[i*veclen : (i+1)*veclen : (i+1)*veclen]
+	}
+	return ret, nil
+}
+
+//LoadMFC reads the sphinx feature file at path, see ReadMFC.
+func LoadMFC(path string, veclen int) ([][]float32, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("mfc error:%v", err)
+	}
+	defer f.Close()
+	return ReadMFC(f, veclen)
+}
+
+//WriteMFC writes frames to w as a sphinx feature file in the byte order order. All frames must have the same number of coefficients.
+func WriteMFC(w io.Writer, frames [][]float32, order binary.ByteOrder) error {
+	var veclen int
+	if len(frames) > 0 {
+		veclen = len(frames[0])
+	}
+	buf := make([]byte, 4+len(frames)*veclen*4)
+	order.PutUint32(buf, uint32(len(frames)*veclen))
+	off := 4
+	for i, frame := range frames {
+		if len(frame) != veclen {
+			return fmt.Errorf("mfc error:frame %d has %d coefficients, want %d", i, len(frame), veclen)
+		}
+		for _, v := range frame {
+			order.PutUint32(buf[off:], math.Float32bits(v))
+			off += 4
+		}
+	}
+	if _, err := w.Write(buf); err != nil {
+		return fmt.Errorf("mfc error:%v", err)
+	}
+	return nil
+}
+
+//SaveMFC writes frames to a sphinx feature file at path, in big-endian byte order like the SphinxTrain tools.
+func SaveMFC(path string, frames [][]float32) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("mfc error:%v", err)
+	}
+	if err := WriteMFC(f, frames, binary.BigEndian); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,74 @@
+package pocketsphinx
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMFCRoundTrip(t *testing.T) {
+	frames := [][]float32{{1, -2.5, 3}, {0, 0.125, -1e6}}
+	tests := []struct {
+		name  string
+		order binary.ByteOrder
+	}{
+		{"big endian", binary.BigEndian},
+		{"little endian", binary.LittleEndian},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteMFC(&buf, frames, tt.order); err != nil {
+				t.Fatal(err)
+			}
+			if buf.Len() != 4+6*4 || tt.order.Uint32(buf.Bytes()) != 6 {
+				t.Errorf("got header %v for %d bytes", buf.Bytes()[:4], buf.Len())
+			}
+			got, err := ReadMFC(&buf, 3)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, frames) {
+				t.Errorf("got %v, want %v", got, frames)
+			}
+		})
+	}
+}
+
+func TestReadMFCErrors(t *testing.T) {
+	var six bytes.Buffer
+	if err := WriteMFC(&six, [][]float32{{1, 2, 3, 4, 5, 6}}, binary.BigEndian); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		data   []byte
+		veclen int
+		err    string
+	}{
+		{name: "invalid vector length", data: six.Bytes(), veclen: 0, err: "invalid vector length"},
+		{name: "short header", data: []byte{0, 0}, veclen: 13, err: "short header"},
+		{name: "size mismatch", data: six.Bytes()[:len(six.Bytes())-4], veclen: 3, err: "doesn't match file size"},
+		{name: "not a multiple", data: six.Bytes(), veclen: 4, err: "isn't a multiple"},
+	}
+	for _, tt := range tests {
+		if _, err := ReadMFC(bytes.NewReader(tt.data), tt.veclen); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: ReadMFC error %v, want %q", tt.name, err, tt.err)
+		}
+	}
+}
+
+func TestWriteMFCRagged(t *testing.T) {
+	if err := WriteMFC(&bytes.Buffer{}, [][]float32{{1, 2}, {3}}, binary.BigEndian); err == nil {
+		t.Error("frames of different lengths written")
+	}
+	var buf bytes.Buffer
+	if err := WriteMFC(&buf, nil, binary.BigEndian); err != nil {
+		t.Fatal(err)
+	}
+	if frames, err := ReadMFC(&buf, 13); err != nil || len(frames) != 0 {
+		t.Errorf("empty file read as %v, %v", frames, err)
+	}
+}
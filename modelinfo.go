@@ -0,0 +1,181 @@
+package pocketsphinx
+
+/*
+#include <pocketsphinx.h>
+*/
+import "C"
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+//ModelInfo describes an acoustic model from its feat.params and mdef files.
+type ModelInfo struct {
+	//Path is the model directory.
+	Path string `json:"path"`
+	//SampleRate is the sampling rate the model expects, from -samprate in feat.params or else inferred from the upper edge of its filter bank.
+	SampleRate float64 `json:"sample_rate"`
+	//FeatureType is the type of the feature vectors, e.g. 1s_c_d_dd.
+	FeatureType string `json:"feature_type"`
+	//CepSize is the number of coefficients of the cepstral vectors.
+	CepSize int `json:"cep_size"`
+	//Senones is the number of tied states of the model.
+	Senones int `json:"senones"`
+	//Phones are the context independent phones of the model, including SIL and fillers.
+	Phones []string `json:"phones"`
+	//Params are the front end flags of feat.params, without their leading dash.
+	Params map[string]string `json:"params"`
+}
+
+//ModelInfo describes the acoustic model of the decoder, so code can check it suits the audio and dictionary it is used with.
+func (p *PocketSphinx) ModelInfo() (ModelInfo, error) {
+	if err := p.acquire(); err != nil {
+		return ModelInfo{}, err
+	}
+	psConfig := C.ps_get_config(p.ps)
+	hmm := getStringParam(psConfig, "-hmm")
+	mdef := getStringParam(psConfig, "-mdef")
+	p.release()
+	if hmm == "" {
+		return ModelInfo{}, errors.New("model error:no acoustic model directory (-hmm)")
+	}
+	return readModelInfo(hmm, mdef)
+}
+
+//ReadModelInfo describes the acoustic model in the directory dir.
+func ReadModelInfo(dir string) (ModelInfo, error) {
+	return readModelInfo(dir, "")
+}
+
+func readModelInfo(dir, mdef string) (ModelInfo, error) {
+	info := ModelInfo{Path: dir, FeatureType: "1s_c_d_dd", CepSize: 13, Params: make(map[string]string)}
+	if err := readFeatParams(filepath.Join(dir, "feat.params"), info.Params); err != nil && !os.IsNotExist(err) {
+		return info, fmt.Errorf("feat.params error:%v", err)
+	}
+	if v, ok := info.Params["feat"]; ok {
+		info.FeatureType = v
+	}
+	if v, ok := info.Params["ceplen"]; ok {
+		if n, err := strconv.Atoi(v); err == nil {
+			info.CepSize = n
+		}
+	}
+	info.SampleRate = 16000
+	if v, ok := info.Params["samprate"]; ok {
+		if rate, err := strconv.ParseFloat(v, 64); err == nil {
+			info.SampleRate = rate
+		}
+	} else if v, ok := info.Params["upperf"]; ok {
+		if upperf, err := strconv.ParseFloat(v, 64); err == nil && upperf <= 4000 {
+			info.SampleRate = 8000
+		}
+	}
+
+	if mdef == "" {
+		mdef = filepath.Join(dir, "mdef")
+		if _, err := os.Stat(mdef); err != nil {
+			mdef = filepath.Join(dir, "mdef.txt")
+		}
+	}
+	data, err := os.ReadFile(mdef)
+	if err != nil {
+		return info, fmt.Errorf("mdef error:%v", err)
+	}
+	if bytes.HasPrefix(data, []byte("BMDF")) || bytes.HasPrefix(data, []byte("FDMB")) {
+		err = parseBinMdef(data, &info)
+	} else {
+		err = parseTextMdef(data, &info)
+	}
+	if err != nil {
+		return info, fmt.Errorf("mdef error:%v", err)
+	}
+	return info, nil
+}
+
+//readFeatParams reads the "-flag value" lines of a feat.params file into params.
+func readFeatParams(path string, params map[string]string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) < 2 || !strings.HasPrefix(fields[0], "-") {
+			continue
+		}
+		params[fields[0][1:]] = fields[1]
+	}
+	return s.Err()
+}
+
+//parseTextMdef reads the counts and base phones of a text model definition.
+func parseTextMdef(data []byte, info *ModelInfo) error {
+	s := bufio.NewScanner(bytes.NewReader(data))
+	nBase := -1
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if len(fields) == 2 {
+			if n, err := strconv.Atoi(fields[0]); err == nil {
+				switch fields[1] {
+				case "n_base":
+					nBase = n
+				case "n_tied_state":
+					info.Senones = n
+				}
+				continue
+			}
+		}
+		//Phone lines start with the base phones, whose left context is "-".
+		if len(fields) > 3 && fields[1] == "-" && len(info.Phones) < nBase {
+			info.Phones = append(info.Phones, fields[0])
+		}
+	}
+	if err := s.Err(); err != nil {
+		return err
+	}
+	if nBase < 0 || len(info.Phones) != nBase {
+		return errors.New("not a model definition")
+	}
+	return nil
+}
+
+//parseBinMdef reads the counts and base phones of a binary model definition, as written by sphinxbase's bin_mdef_write.
+func parseBinMdef(data []byte, info *ModelInfo) error {
+	if len(data) < 12 {
+		return errors.New("short header")
+	}
+	var order binary.ByteOrder = binary.LittleEndian
+	if order.Uint32(data) != 0x46444d42 {
+		order = binary.BigEndian
+	}
+	off := 12 + int(order.Uint32(data[8:]))
+	//n_ciphone, n_phone, n_emit_state, n_ci_sen, n_sen, n_tmat, n_sseq, n_ctx, n_cd_tree and sil.
+	if len(data) < off+40 {
+		return errors.New("short header")
+	}
+	nBase := int(order.Uint32(data[off:]))
+	info.Senones = int(order.Uint32(data[off+16:]))
+	off += 40
+	for i := 0; i < nBase; i++ {
+		end := bytes.IndexByte(data[off:], 0)
+		if end < 0 {
+			return errors.New("truncated phone names")
+		}
+		info.Phones = append(info.Phones, string(data[off:off+end]))
+		off += end + 1
+	}
+	return nil
+}
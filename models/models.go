@@ -0,0 +1,407 @@
+//Package models downloads published CMU Sphinx acoustic models, dictionaries and language models by name, and caches them on disk.
+//
+//	m := models.NewManager("")
+//	m.AllowUnverified = true //The catalog has no checksums yet.
+//	paths, err := m.Fetch(ctx, "en-us")
+//	decoder, err := pocketsphinx.New(pocketsphinx.WithHMM(paths.HMM), pocketsphinx.WithDict(paths.Dict), pocketsphinx.WithLM(paths.LM))
+//
+//Files are verified against their SHA256 checksum after downloading, and those without one are refused with ErrNoChecksum, unless the Manager allows unverified downloads. The checksums of all files are recorded when first downloaded, so later corruption of the cache is detected by Verify.
+package models
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//ErrUnknownModel is returned for names missing from the catalog.
+var ErrUnknownModel = errors.New("unknown model")
+
+//ErrChecksum is returned when a downloaded or cached file doesn't match its checksum.
+var ErrChecksum = errors.New("checksum mismatch")
+
+//ErrNoChecksum is returned when fetching a model with files without a SHA256 checksum, unless Manager.AllowUnverified is set.
+var ErrNoChecksum = errors.New("no checksum")
+
+//Format is how a downloaded file is stored.
+type Format int
+
+const (
+	//Plain files are stored as downloaded.
+	Plain Format = iota
+	//Gzip files are decompressed.
+	Gzip
+	//TarGz archives are extracted into a directory.
+	TarGz
+)
+
+//File is a file of a model.
+type File struct {
+	URL string
+	//Path is where the file is stored, or the archive extracted, relative to the directory of the model.
+	Path   string
+	Format Format
+	//SHA256 is the hex encoded checksum of the downloaded file, before decompression. If empty, the file is only downloaded if the Manager allows unverified downloads.
+	SHA256 string
+}
+
+//Model is a set of files making up a model, and the paths of its parts relative to the directory of the model. Parts a model doesn't provide are empty.
+type Model struct {
+	Files []File
+	HMM   string
+	Dict  string
+	LM    string
+}
+
+//Paths are the absolute paths of the parts of a downloaded model, to configure a decoder with.
+type Paths struct {
+	HMM  string
+	Dict string
+	LM   string
+}
+
+const sfModels = "https://sourceforge.net/projects/cmusphinx/files/Acoustic%20and%20Language%20Models/"
+
+//Catalog lists the models published by the CMU Sphinx project, by language code. NewManager copies it, so Register adds models to a single Manager.
+//
+//The project doesn't publish SHA256 checksums of its files, so until they are pinned here, fetching them needs a Manager with AllowUnverified set, or a model registered with the checksums of files the caller verified.
+var Catalog = map[string]Model{
+	"en-us": {
+		Files: []File{
+			{URL: sfModels + "US%20English/cmusphinx-en-us-ptm-5.2.tar.gz/download", Path: "acoustic", Format: TarGz},
+			{URL: sfModels + "US%20English/cmudict-en-us.dict/download", Path: "cmudict-en-us.dict"},
+			{URL: sfModels + "US%20English/en-70k-0.2.lm.gz/download", Path: "en-70k-0.2.lm", Format: Gzip},
+		},
+		HMM:  "acoustic/cmusphinx-en-us-ptm-5.2",
+		Dict: "cmudict-en-us.dict",
+		LM:   "en-70k-0.2.lm",
+	},
+	"fr-fr": {
+		Files: []File{
+			{URL: sfModels + "French/cmusphinx-fr-ptm-5.2.tar.gz/download", Path: "acoustic", Format: TarGz},
+			{URL: sfModels + "French/fr.dict/download", Path: "fr.dict"},
+			{URL: sfModels + "French/fr-small.lm.bin/download", Path: "fr-small.lm.bin"},
+		},
+		HMM:  "acoustic/cmusphinx-fr-ptm-5.2",
+		Dict: "fr.dict",
+		LM:   "fr-small.lm.bin",
+	},
+}
+
+//sumsFile records the checksums of the downloaded files of a model, and marks it as complete.
+const sumsFile = "SHA256SUMS"
+
+//Manager downloads models into a cache directory. It is safe for concurrent use.
+type Manager struct {
+	//Dir is the cache directory, with a subdirectory per model.
+	Dir string
+	//Client downloads the files, http.DefaultClient if nil.
+	Client *http.Client
+	//AllowUnverified downloads files without a SHA256 checksum, trusting them the first time. Otherwise Fetch refuses them with ErrNoChecksum.
+	AllowUnverified bool
+
+	mu      sync.Mutex
+	catalog map[string]Model
+	fetches map[string]*sync.Mutex
+}
+
+//NewManager creates Manager caching models in dir, or in pocketsphinx under the user's cache directory if dir is empty.
+func NewManager(dir string) *Manager {
+	if dir == "" {
+		if cache, err := os.UserCacheDir(); err == nil {
+			dir = filepath.Join(cache, "pocketsphinx")
+		} else {
+			dir = filepath.Join(os.TempDir(), "pocketsphinx")
+		}
+	}
+	m := &Manager{Dir: dir, catalog: make(map[string]Model), fetches: make(map[string]*sync.Mutex)}
+	for name, model := range Catalog {
+		m.catalog[name] = model
+	}
+	return m
+}
+
+//Register adds model to the catalog of m as name, replacing any model of that name, e.g. to pin checksums or use a mirror.
+func (m *Manager) Register(name string, model Model) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.catalog[name] = model
+}
+
+//Names gets the names of the models of the catalog, in sorted order.
+func (m *Manager) Names() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ret := make([]string, 0, len(m.catalog))
+	for name := range m.catalog {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+//Cached gets the paths of the model name if it was downloaded, without downloading it.
+func (m *Manager) Cached(name string) (Paths, bool) {
+	model, err := m.model(name)
+	if err != nil {
+		return Paths{}, false
+	}
+	dir := m.modelDir(name)
+	if _, err := os.Stat(filepath.Join(dir, sumsFile)); err != nil {
+		return Paths{}, false
+	}
+	return model.paths(dir), true
+}
+
+//Fetch gets the paths of the model name, downloading it first unless it is cached.
+func (m *Manager) Fetch(ctx context.Context, name string) (Paths, error) {
+	model, err := m.model(name)
+	if err != nil {
+		return Paths{}, err
+	}
+	lock := m.fetchLock(name)
+	lock.Lock()
+	defer lock.Unlock()
+	if paths, ok := m.Cached(name); ok {
+		return paths, nil
+	}
+
+	if !m.AllowUnverified {
+		for _, f := range model.Files {
+			if f.SHA256 == "" {
+				return Paths{}, fmt.Errorf("models %s error:%w:%s", name, ErrNoChecksum, f.Path)
+			}
+		}
+	}
+
+	dir := m.modelDir(name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return Paths{}, fmt.Errorf("models error:%v", err)
+	}
+	var sums strings.Builder
+	for _, f := range model.Files {
+		sum, err := m.download(ctx, dir, f)
+		if err != nil {
+			return Paths{}, fmt.Errorf("models %s error:%w", name, err)
+		}
+		fmt.Fprintf(&sums, "%s  %s\n", sum, f.Path)
+	}
+	if err := os.WriteFile(filepath.Join(dir, sumsFile), []byte(sums.String()), 0o644); err != nil {
+		return Paths{}, fmt.Errorf("models error:%v", err)
+	}
+	return model.paths(dir), nil
+}
+
+//Verify checks that the cached plain files of the model name still match the checksums recorded when they were downloaded. Extracted and decompressed files can't be checked.
+func (m *Manager) Verify(name string) error {
+	model, err := m.model(name)
+	if err != nil {
+		return err
+	}
+	dir := m.modelDir(name)
+	f, err := os.Open(filepath.Join(dir, sumsFile))
+	if err != nil {
+		return fmt.Errorf("models %s error:not downloaded", name)
+	}
+	defer f.Close()
+	sums := make(map[string]string)
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		if fields := strings.Fields(s.Text()); len(fields) == 2 {
+			sums[fields[1]] = fields[0]
+		}
+	}
+	for _, file := range model.Files {
+		if file.Format != Plain {
+			continue
+		}
+		sum, err := fileSum(filepath.Join(dir, file.Path))
+		if err != nil {
+			return fmt.Errorf("models %s error:%v", name, err)
+		}
+		if sum != sums[file.Path] {
+			return fmt.Errorf("models %s error:%w:%s", name, ErrChecksum, file.Path)
+		}
+	}
+	return nil
+}
+
+//Remove deletes the cached model name.
+func (m *Manager) Remove(name string) error {
+	lock := m.fetchLock(name)
+	lock.Lock()
+	defer lock.Unlock()
+	return os.RemoveAll(m.modelDir(name))
+}
+
+func (m *Manager) model(name string) (Model, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	model, ok := m.catalog[name]
+	if !ok {
+		return Model{}, fmt.Errorf("models %s error:%w", name, ErrUnknownModel)
+	}
+	return model, nil
+}
+
+func (m *Manager) fetchLock(name string) *sync.Mutex {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	lock, ok := m.fetches[name]
+	if !ok {
+		lock = &sync.Mutex{}
+		m.fetches[name] = lock
+	}
+	return lock
+}
+
+func (m *Manager) modelDir(name string) string {
+	return filepath.Join(m.Dir, name)
+}
+
+func (model Model) paths(dir string) Paths {
+	var p Paths
+	if model.HMM != "" {
+		p.HMM = filepath.Join(dir, model.HMM)
+	}
+	if model.Dict != "" {
+		p.Dict = filepath.Join(dir, model.Dict)
+	}
+	if model.LM != "" {
+		p.LM = filepath.Join(dir, model.LM)
+	}
+	return p
+}
+
+//download fetches f into dir, returning the checksum of the downloaded file.
+func (m *Manager) download(ctx context.Context, dir string, f File) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.URL, nil)
+	if err != nil {
+		return "", err
+	}
+	client := m.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s:%s", f.URL, resp.Status)
+	}
+
+	//Download to a temporary file first, so nothing is stored unless the checksum matches.
+	tmp, err := os.CreateTemp(dir, ".download-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, h), resp.Body); err != nil {
+		return "", err
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+	if f.SHA256 != "" && !strings.EqualFold(sum, f.SHA256) {
+		return "", fmt.Errorf("%w:%s", ErrChecksum, f.URL)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	dst := filepath.Join(dir, filepath.FromSlash(f.Path))
+	switch f.Format {
+	case TarGz:
+		err = extractTarGz(tmp, dst)
+	case Gzip:
+		err = gunzip(tmp, dst)
+	default:
+		tmp.Close()
+		err = os.Rename(tmp.Name(), dst)
+	}
+	return sum, err
+}
+
+func gunzip(r io.Reader, dst string) error {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+	return writeFile(dst, zr)
+}
+
+func extractTarGz(r io.Reader, dst string) error {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+	tr := tar.NewReader(zr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := filepath.Clean(filepath.FromSlash(hdr.Name))
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("archive entry %s outside of the model directory", hdr.Name)
+		}
+		path := filepath.Join(dst, name)
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				return err
+			}
+			if err := writeFile(path, tr); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func writeFile(path string, r io.Reader) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func fileSum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
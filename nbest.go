@@ -0,0 +1,129 @@
+package pocketsphinx
+
+/*
+#include <pocketsphinx.h>
+*/
+import "C"
+
+//NbestIter walks the N-best hypotheses of the last utterance, best first.
+//
+//	it := p.Nbest()
+//	defer it.Close()
+//	for it.Next() {
+//		hyp := it.Hyp()
+//	}
+type NbestIter struct {
+	it      *C.ps_nbest_t
+	started bool
+}
+
+//Nbest returns an iterator over the N-best hypotheses. It must be closed when done, unless Next has returned false, and not be used while the decoder processes audio.
+func (p *PocketSphinx) Nbest() *NbestIter {
+	if p.acquire() != nil {
+		return &NbestIter{}
+	}
+	defer p.release()
+	return p.nbest()
+}
+
+func (p *PocketSphinx) nbest() *NbestIter {
+	return &NbestIter{it: C.ps_nbest(p.ps)}
+}
+
+//Next advances to the next hypothesis, returning false when there are no more.
+func (it *NbestIter) Next() bool {
+	if !it.started {
+		it.started = true
+		return it.it != nil
+	}
+	if it.it == nil {
+		return false
+	}
+	it.it = C.ps_nbest_next(it.it)
+	return it.it != nil
+}
+
+//Hyp gets the current hypothesis. The text of a hypothesis containing only silence is empty. It returns the zero Result once the iterator is exhausted or closed.
+func (it *NbestIter) Hyp() Result {
+	if it.it == nil {
+		return Result{}
+	}
+	var score C.int32
+	var text string
+	if charp := C.ps_nbest_hyp(it.it, &score); charp != nil {
+		text = C.GoString(charp)
+	}
+	return Result{Text: text, Score: int64(score)}
+}
+
+//Segments gets the word segmentation of the current hypothesis, or nil once the iterator is exhausted or closed.
+func (it *NbestIter) Segments() []Segment {
+	if it.it == nil {
+		return nil
+	}
+	return collectSegments(C.ps_nbest_seg(it.it))
+}
+
+//Close releases the iterator. It is safe to call more than once.
+func (it *NbestIter) Close() {
+	if it.it != nil {
+		C.ps_nbest_free(it.it)
+		it.it = nil
+	}
+}
+
+func (p *PocketSphinx) GetNbest(numNbest int) []Result {
+	return p.getNbest(numNbest, false)
+}
+
+//GetNbestSegments is like GetNbest, but also fills in the word segmentation of every hypothesis.
+func (p *PocketSphinx) GetNbestSegments(numNbest int) []Result {
+	return p.getNbest(numNbest, true)
+}
+
+//GetNbestInto is like GetNbest, but appends to dst[:0], reusing its memory and hypothesis texts.
+func (p *PocketSphinx) GetNbestInto(dst []Result, numNbest int) []Result {
+	ret := dst[:0]
+	if p.acquire() != nil {
+		return ret
+	}
+	defer p.release()
+	it := p.nbest()
+	defer it.Close()
+	for len(ret) < numNbest && it.Next() {
+		var prev string
+		if len(ret) < cap(ret) {
+			prev = ret[:len(ret)+1][len(ret)].Text
+		}
+		var score C.int32
+		var text string
+		if charp := C.ps_nbest_hyp(it.it, &score); charp != nil {
+			text = goStringReuse(charp, prev)
+		}
+		ret = append(ret, Result{Text: text, Score: int64(score)})
+	}
+	return ret
+}
+
+func (p *PocketSphinx) getNbest(numNbest int, withSegments bool) []Result {
+	if numNbest < 0 {
+		numNbest = 0
+	}
+	ret := make([]Result, 0, numNbest)
+	if p.acquire() != nil {
+		return ret
+	}
+	defer p.release()
+
+	it := p.nbest()
+	defer it.Close()
+	for len(ret) < numNbest && it.Next() {
+		hyp := it.Hyp()
+		if withSegments {
+			hyp.Segments = it.Segments()
+		}
+		ret = append(ret, hyp)
+	}
+
+	return ret
+}
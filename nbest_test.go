@@ -0,0 +1,33 @@
+package pocketsphinx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNbestIterEmpty(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps int
+	}{
+		{name: "unstarted", steps: 0},
+		{name: "exhausted", steps: 1},
+		{name: "past the end", steps: 3},
+	}
+	for _, tt := range tests {
+		it := &NbestIter{}
+		for i := 0; i < tt.steps; i++ {
+			if it.Next() {
+				t.Fatalf("%s: Next of an empty iterator returned true", tt.name)
+			}
+		}
+		if hyp := it.Hyp(); !reflect.DeepEqual(hyp, Result{}) {
+			t.Errorf("%s: Hyp got %+v", tt.name, hyp)
+		}
+		if segs := it.Segments(); segs != nil {
+			t.Errorf("%s: Segments got %v", tt.name, segs)
+		}
+		it.Close()
+		it.Close()
+	}
+}
@@ -0,0 +1,308 @@
+package pocketsphinx
+
+/*
+#include <pocketsphinx.h>
+#include <stdlib.h>
+#include <string.h>
+int arg_type(char const *name){
+    arg_t const *defn;
+    for (defn = ps_args(); defn->name != NULL; ++defn) {
+        if (strcmp(defn->name, name) == 0)
+            return defn->type & ~ARG_REQUIRED;
+    }
+    return 0;
+}
+*/
+import "C"
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"unsafe"
+)
+
+//Option sets a decoder configuration flag for New.
+type Option func(psConfig *C.cmd_ln_t)
+
+//WithHMM sets the acoustic model directory (-hmm).
+func WithHMM(path string) Option {
+	return WithString("-hmm", path)
+}
+
+//WithDict sets the pronunciation dictionary file (-dict).
+func WithDict(path string) Option {
+	return WithString("-dict", path)
+}
+
+//WithLM sets the n-gram language model file (-lm).
+func WithLM(path string) Option {
+	return WithString("-lm", path)
+}
+
+//WithJSGFFile sets the JSGF grammar file (-jsgf).
+func WithJSGFFile(path string) Option {
+	return WithString("-jsgf", path)
+}
+
+//WithKeyphrase sets the keyphrase to spot (-keyphrase).
+func WithKeyphrase(keyphrase string) Option {
+	return WithString("-keyphrase", keyphrase)
+}
+
+//WithSampleRate sets the sampling rate of the input audio (-samprate).
+func WithSampleRate(samprate float64) Option {
+	return WithFloat("-samprate", samprate)
+}
+
+//WithInputEndian sets the byte order of the audio passed to ProcessRaw and ProcessRawBytes, "little" or "big" (-input_endian). Samples in a byte order other than the machine's are swapped before they are decoded, so big-endian sources such as AIFF files or RTP L16 payloads can be passed as they are. Audio the binding converts itself, e.g. from float32, G.711, WAV files or ProcessStream, is unaffected, and the front end is always configured with the machine's byte order, which GetOption reports.
+func WithInputEndian(endian string) Option {
+	return WithString("-input_endian", endian)
+}
+
+//takeInputEndian sets the -input_endian flag of psConfig to the machine's byte order, as the front end would also swap the audio converted by the binding, and returns the byte order of the input of ProcessRaw, nil if it is the machine's.
+func takeInputEndian(psConfig *C.cmd_ln_t) binary.ByteOrder {
+	order := inputOrder(getStringParam(psConfig, "-input_endian"))
+	setStringParam(psConfig, "-input_endian", nativeEndian())
+	return order
+}
+
+//inputOrder gets the byte order named by an -input_endian value, nil if it is the machine's. Like sphinx, anything but "big" is little-endian.
+func inputOrder(endian string) binary.ByteOrder {
+	big := endian == "big"
+	if big == (nativeEndian() == "big") {
+		return nil
+	}
+	if big {
+		return binary.BigEndian
+	}
+	return binary.LittleEndian
+}
+
+//nativeEndian gets the byte order of the machine as an -input_endian value.
+func nativeEndian() string {
+	x := uint16(1)
+	if *(*byte)(unsafe.Pointer(&x)) == 1 {
+		return "little"
+	}
+	return "big"
+}
+
+//WithTelephone configures the front end for 8kHz narrowband telephone audio. It must be combined with an acoustic model trained on 8kHz audio, such as en-us-8khz.
+func WithTelephone() Option {
+	return func(psConfig *C.cmd_ln_t) {
+		setFloatParam(psConfig, "-samprate", 8000)
+		setIntParam(psConfig, "-nfft", 256)
+	}
+}
+
+//WithBeam sets the beam width applied to every frame in Viterbi search (-beam).
+func WithBeam(beam float64) Option {
+	return WithFloat("-beam", beam)
+}
+
+//WithAGC sets the automatic gain control mode (-agc).
+func WithAGC(mode AGCMode) Option {
+	return WithString("-agc", string(mode))
+}
+
+//WithRemoveNoise enables or disables the subtraction of stationary background noise (-remove_noise).
+func WithRemoveNoise(remove bool) Option {
+	return WithBool("-remove_noise", remove)
+}
+
+//WithVAD sets the voice activity detection threshold in dB above the noise floor (-vad_threshold), and the number of frames kept before speech (-vad_prespeech), of silence ending speech (-vad_postspeech) and of speech starting it (-vad_startspeech). Zero values keep the defaults. See the fields of Config for their effect.
+func WithVAD(threshold float64, prespeech, postspeech, startspeech int64) Option {
+	return func(psConfig *C.cmd_ln_t) {
+		if threshold != 0 {
+			setFloatParam(psConfig, "-vad_threshold", threshold)
+		}
+		if prespeech != 0 {
+			setIntParam(psConfig, "-vad_prespeech", prespeech)
+		}
+		if postspeech != 0 {
+			setIntParam(psConfig, "-vad_postspeech", postspeech)
+		}
+		if startspeech != 0 {
+			setIntParam(psConfig, "-vad_startspeech", startspeech)
+		}
+	}
+}
+
+//WithMMap enables or disables memory mapping of model files (-mmap).
+func WithMMap(mmap bool) Option {
+	return WithBool("-mmap", mmap)
+}
+
+//WithString sets an arbitrary string flag, e.g. WithString("-fdict", path).
+func WithString(key, val string) Option {
+	return func(psConfig *C.cmd_ln_t) {
+		setStringParam(psConfig, key, val)
+	}
+}
+
+//WithInt sets an arbitrary integer flag, e.g. WithInt("-maxwpf", 10).
+func WithInt(key string, val int64) Option {
+	return func(psConfig *C.cmd_ln_t) {
+		setIntParam(psConfig, key, val)
+	}
+}
+
+//WithFloat sets an arbitrary floating point flag, e.g. WithFloat("-kws_threshold", 1e-20).
+func WithFloat(key string, val float64) Option {
+	return func(psConfig *C.cmd_ln_t) {
+		setFloatParam(psConfig, key, val)
+	}
+}
+
+//WithBool sets an arbitrary boolean flag, e.g. WithBool("-bestpath", false).
+func WithBool(key string, val bool) Option {
+	return func(psConfig *C.cmd_ln_t) {
+		setBoolParam(psConfig, key, val)
+	}
+}
+
+//SetOption sets the decoder flag key, e.g. "-beam" or "beam", converting value to the type declared for it by pocketsphinx. Most flags are only read when the decoder is initialized, so call Reinit to apply them. -logfn is applied at once with SetLogFile, and -input_endian at once to ProcessRaw and ProcessRawBytes.
+func (p *PocketSphinx) SetOption(key string, value interface{}) error {
+	if err := p.acquire(); err != nil {
+		return err
+	}
+	defer p.release()
+	key = optionKey(key)
+	if key == "-logfn" {
+		//Set on the decoder rather than in the config, as sphinx would log all decoders to the file on Reinit.
+		val, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("option %s error:want string, got %T", key, value)
+		}
+		return p.setLogFile(val)
+	}
+	if key == "-input_endian" {
+		//Applied at once in Go rather than by the front end, see WithInputEndian.
+		val, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("option %s error:want string, got %T", key, value)
+		}
+		p.inputOrder = inputOrder(val)
+		return nil
+	}
+	psConfig := C.ps_get_config(p.ps)
+	switch argType(key) {
+	case C.ARG_INTEGER:
+		val, ok := toInt(value)
+		if !ok {
+			return fmt.Errorf("option %s error:want integer, got %T", key, value)
+		}
+		setIntParam(psConfig, key, val)
+	case C.ARG_FLOATING:
+		val, ok := toFloat(value)
+		if !ok {
+			return fmt.Errorf("option %s error:want float, got %T", key, value)
+		}
+		setFloatParam(psConfig, key, val)
+	case C.ARG_STRING:
+		val, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("option %s error:want string, got %T", key, value)
+		}
+		setStringParam(psConfig, key, val)
+	case C.ARG_BOOLEAN:
+		val, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("option %s error:want bool, got %T", key, value)
+		}
+		setBoolParam(psConfig, key, val)
+	case 0:
+		return fmt.Errorf("unknown option:%s", key)
+	default:
+		return fmt.Errorf("option %s error:unsupported type", key)
+	}
+	return nil
+}
+
+//GetOption gets the current value of the decoder flag key as an int64, float64, string or bool depending on its declared type.
+func (p *PocketSphinx) GetOption(key string) (interface{}, error) {
+	if err := p.acquire(); err != nil {
+		return nil, err
+	}
+	defer p.release()
+	key = optionKey(key)
+	psConfig := C.ps_get_config(p.ps)
+	keyPtr := C.CString(key)
+	defer C.free(unsafe.Pointer(keyPtr))
+	switch argType(key) {
+	case C.ARG_INTEGER:
+		return int64(C.cmd_ln_int_r(psConfig, keyPtr)), nil
+	case C.ARG_FLOATING:
+		return float64(C.cmd_ln_float_r(psConfig, keyPtr)), nil
+	case C.ARG_STRING:
+		return getStringParam(psConfig, key), nil
+	case C.ARG_BOOLEAN:
+		return C.cmd_ln_int_r(psConfig, keyPtr) != 0, nil
+	case 0:
+		return nil, fmt.Errorf("unknown option:%s", key)
+	default:
+		return nil, fmt.Errorf("option %s error:unsupported type", key)
+	}
+}
+
+//Reinit reinitializes the decoder from its current configuration, applying flags changed with SetOption.
+func (p *PocketSphinx) Reinit() error {
+	if err := p.acquire(); err != nil {
+		return err
+	}
+	defer p.release()
+	if err := reinitDecoder(p.ps, nil); err != nil {
+		return err
+	}
+	p.afterInit(C.ps_get_config(p.ps))
+	return nil
+}
+
+func optionKey(key string) string {
+	if !strings.HasPrefix(key, "-") {
+		return "-" + key
+	}
+	return key
+}
+
+func argType(key string) C.int {
+	keyPtr := C.CString(key)
+	defer C.free(unsafe.Pointer(keyPtr))
+	return C.arg_type(keyPtr)
+}
+
+func toInt(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint:
+		return int64(v), true
+	case uint8:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	}
+	return 0, false
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	val, ok := toInt(value)
+	return float64(val), ok
+}
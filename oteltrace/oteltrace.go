@@ -0,0 +1,49 @@
+//Package oteltrace traces pocketsphinx decode operations with OpenTelemetry.
+//
+//	pocketsphinx.SetTracer(oteltrace.New(otel.Tracer("pocketsphinx")))
+package oteltrace
+
+import (
+	"context"
+
+	"github.com/andyleap/pocketsphinx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+//Tracer implements pocketsphinx.Tracer with an OpenTelemetry tracer. Spans are named pocketsphinx.<operation>, e.g. pocketsphinx.TranscribeFile.
+type Tracer struct {
+	t trace.Tracer
+}
+
+var _ pocketsphinx.Tracer = (*Tracer)(nil)
+
+//New creates Tracer starting spans with t.
+func New(t trace.Tracer) *Tracer {
+	return &Tracer{t: t}
+}
+
+//Start starts a span for op.
+func (t *Tracer) Start(ctx context.Context, op string) (context.Context, pocketsphinx.Span) {
+	ctx, span := t.t.Start(ctx, "pocketsphinx."+op)
+	return ctx, otelSpan{span}
+}
+
+type otelSpan struct {
+	span trace.Span
+}
+
+func (s otelSpan) End(attrs pocketsphinx.SpanAttributes, err error) {
+	s.span.SetAttributes(
+		attribute.Float64("pocketsphinx.audio_seconds", attrs.Audio.Seconds()),
+		attribute.String("pocketsphinx.search", attrs.Search),
+		attribute.Int("pocketsphinx.words", attrs.Words),
+		attribute.Float64("pocketsphinx.rtf", attrs.RTF),
+	)
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+}
@@ -0,0 +1,25 @@
+package pocketsphinx
+
+import (
+	"strings"
+
+	"github.com/andyleap/pocketsphinx/decoder"
+)
+
+//PartialHyp is a hypothesis of an utterance in progress. Its first Stable words were the same in the previous call to GetPartialHyp, so they are unlikely to change, while the following ones may still be revised as more audio is decoded.
+type PartialHyp = decoder.PartialHyp
+
+//GetPartialHyp gets the hypothesis of the utterance in progress, with the number of leading words that didn't change since the previous call in the same utterance, for live captions to tell confirmed words from changing ones. It should be called after each chunk of audio is processed.
+func (p *PocketSphinx) GetPartialHyp() (PartialHyp, error) {
+	hyp, err := p.GetHyp()
+	if err != nil {
+		return PartialHyp{}, err
+	}
+	words := strings.Fields(hyp.Text)
+	stable := 0
+	for stable < len(words) && stable < len(p.partial) && words[stable] == p.partial[stable] {
+		stable++
+	}
+	p.partial = append(p.partial[:0], words...)
+	return PartialHyp{Result: hyp, Stable: stable}, nil
+}
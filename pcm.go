@@ -0,0 +1,79 @@
+package pocketsphinx
+
+//Float32ToPCM appends the float samples in src, in the range -1 to 1, to dst as 16-bit pcm. Samples out of range are clipped and NaNs become silence.
+func Float32ToPCM(dst []int16, src []float32) []int16 {
+	n := len(dst)
+	if cap(dst)-n < len(src) {
+		grown := make([]int16, n, n+len(src))
+		copy(grown, dst)
+		dst = grown
+	}
+	dst = dst[:n+len(src)]
+	out := dst[n:]
+	for i, f := range src {
+		v := f * 32767
+		if v > 32767 {
+			v = 32767
+		} else if v < -32768 {
+			v = -32768
+		} else if v != v {
+			v = 0
+		}
+		out[i] = int16(v)
+	}
+	return dst
+}
+
+//ProcessFloat32 processes single channel float audio in the range -1 to 1, as delivered by WebAudio, CoreAudio or WASAPI, like ProcessRaw.
+func (p *PocketSphinx) ProcessFloat32(raw []float32, noSearch, fullUtt bool) (int, error) {
+	if err := p.acquire(); err != nil {
+		return 0, err
+	}
+	defer p.release()
+	p.conv = Float32ToPCM(p.conv[:0], raw)
+	return p.processRaw(p.conv, noSearch, fullUtt)
+}
+
+//Downmix appends the interleaved audio with the given number of channels in src to dst as a single channel, averaging the channels of each frame. A trailing partial frame is ignored.
+func Downmix(dst []int16, src []int16, channels int) []int16 {
+	if channels <= 1 {
+		return append(dst, src...)
+	}
+	for i := 0; i+channels <= len(src); i += channels {
+		var sum int32
+		for _, s := range src[i : i+channels] {
+			sum += int32(s)
+		}
+		dst = append(dst, int16(sum/int32(channels)))
+	}
+	return dst
+}
+
+//SelectChannel appends one channel of the interleaved audio with the given number of channels in src to dst. Channels are numbered from 0.
+func SelectChannel(dst []int16, src []int16, channels, channel int) []int16 {
+	if channels <= 1 {
+		return append(dst, src...)
+	}
+	for i := channel; i < len(src); i += channels {
+		dst = append(dst, src[i])
+	}
+	return dst
+}
+
+//ProcessInterleaved processes interleaved 16-bit pcm audio with the given number of channels, downmixing it to the single channel the decoder expects, like ProcessRaw.
+func (p *PocketSphinx) ProcessInterleaved(raw []int16, channels int, noSearch, fullUtt bool) (int, error) {
+	if err := p.acquire(); err != nil {
+		return 0, err
+	}
+	defer p.release()
+	p.conv = Downmix(p.conv[:0], raw, channels)
+	return p.processRaw(p.conv, noSearch, fullUtt)
+}
+
+//SwapBytes swaps the byte order of the samples in raw in place, converting between big and little-endian.
+func SwapBytes(raw []int16) {
+	for i, s := range raw {
+		u := uint16(s)
+		raw[i] = int16(u<<8 | u>>8)
+	}
+}
@@ -0,0 +1,125 @@
+package pocketsphinx
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"testing"
+)
+
+//mulawEncode gets the mu-law code word decoding to the value nearest to s.
+func mulawEncode(s int16) byte {
+	best := 0
+	for i, v := range mulawTable {
+		if abs(int(v)-int(s)) < abs(int(mulawTable[best])-int(s)) {
+			best = i
+		}
+	}
+	return byte(best)
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+//decodeHyp decodes a whole utterance passed to p by process and gets its hypothesis.
+func decodeHyp(t *testing.T, p *PocketSphinx, process func(p *PocketSphinx) (int, error)) (Result, error) {
+	t.Helper()
+	if err := p.StartUtt(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := process(p); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.EndUtt(); err != nil {
+		t.Fatal(err)
+	}
+	return p.GetHyp()
+}
+
+func TestInputEndian(t *testing.T) {
+	hmm := testHMM(t)
+	f, err := os.Open("testdata/speech.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, audio, err := ReadWAV(f)
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mulaw := make([]byte, len(audio))
+	for i, s := range audio {
+		mulaw[i] = mulawEncode(s)
+	}
+	//big holds audio as big-endian bytes, and bigRaw the same bytes as samples.
+	big := make([]byte, 2*len(audio))
+	bigRaw := make([]int16, len(audio))
+	for i, s := range audio {
+		binary.BigEndian.PutUint16(big[2*i:], uint16(s))
+		bigRaw[i] = int16(binary.NativeEndian.Uint16(big[2*i:]))
+	}
+
+	newDecoder := func(opts ...Option) *PocketSphinx {
+		p, err := New(append([]Option{WithHMM(hmm), WithDict("testdata/commands.dict"), WithLM("testdata/commands.lm"), WithString("-logfn", os.DevNull)}, opts...)...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { p.Free() })
+		return p
+	}
+	native := newDecoder()
+	bigEndian := newDecoder(WithInputEndian("big"))
+
+	tests := []struct {
+		name string
+		//pcm is the audio, in the machine's byte order, that process passes to the decoder.
+		pcm     []int16
+		process func(p *PocketSphinx) (int, error)
+	}{
+		{
+			name:    "mu-law",
+			pcm:     MulawToPCM(nil, mulaw),
+			process: func(p *PocketSphinx) (int, error) { return p.ProcessMulaw(mulaw, false, true) },
+		},
+		{
+			name:    "ProcessRaw",
+			pcm:     audio,
+			process: func(p *PocketSphinx) (int, error) { return p.ProcessRaw(bigRaw, false, true) },
+		},
+		{
+			name:    "ProcessRawBytes",
+			pcm:     audio,
+			process: func(p *PocketSphinx) (int, error) { return p.ProcessRawBytes(big, false, true) },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, wantErr := decodeHyp(t, native, func(p *PocketSphinx) (int, error) { return p.ProcessRaw(tt.pcm, false, true) })
+			got, err := decodeHyp(t, bigEndian, tt.process)
+			if !errors.Is(err, wantErr) {
+				t.Fatalf("GetHyp error %v, want %v", err, wantErr)
+			}
+			if got.Text != want.Text || got.Score != want.Score {
+				t.Errorf("got %q score %d, want %q score %d", got.Text, got.Score, want.Text, want.Score)
+			}
+		})
+	}
+}
+
+func TestInputOrder(t *testing.T) {
+	//Like sphinx, anything but "big" is little-endian, and the machine's byte order needs no swapping.
+	orders := map[string]binary.ByteOrder{"little": binary.LittleEndian, "big": binary.BigEndian, "": binary.LittleEndian}
+	for endian, order := range orders {
+		want := order
+		if (order == binary.BigEndian) == (nativeEndian() == "big") {
+			want = nil
+		}
+		if got := inputOrder(endian); got != want {
+			t.Errorf("inputOrder(%q) got %v, want %v", endian, got, want)
+		}
+	}
+}
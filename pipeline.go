@@ -0,0 +1,191 @@
+package pocketsphinx
+
+import (
+	"errors"
+	"time"
+)
+
+//PipelineOptions configures NewCommandPipeline.
+type PipelineOptions struct {
+	//WakeWord configures the keyword search listening for the wake word.
+	WakeWord WakeWordOptions
+	//CommandSearch is the name of the search decoding commands, e.g. one added with ParseJSGF or SetLMFile.
+	CommandSearch string
+	//CommandWindow is the longest time a command is listened for after the wake word. It defaults to five seconds.
+	CommandWindow time.Duration
+	//PreRoll is the longest time of audio following the wake word that is passed again to the command search, so the start of a command spoken right after the wake word isn't lost while switching searches. It defaults to one second.
+	PreRoll time.Duration
+}
+
+//Command is the result of listening for a command after a wake word. TimedOut is set if the command window elapsed before the end of speech was detected. Result is empty if nothing was recognized.
+type Command struct {
+	Wake     WakeDetection `json:"wake"`
+	Result   Result        `json:"result"`
+	TimedOut bool          `json:"timed_out"`
+}
+
+//CommandPipeline listens for a wake word with a keyword search, then switches to a command search until the end of the command or of the command window, and back. Audio is passed with ProcessRaw, or written as single channel, little-endian 16-bit pcm bytes.
+type CommandPipeline struct {
+	pcmWriter
+	p         *PocketSphinx
+	wake      *WakeWord
+	kwsSearch string
+	cmdSearch string
+	window    int64
+	preRoll   int
+
+	onWake    func(WakeDetection)
+	onCommand func(Command)
+
+	//history holds the last preRoll samples, total the number of samples passed.
+	history []int16
+	total   int64
+
+	detected  *WakeDetection
+	inCommand bool
+	cmd       WakeDetection
+	cmdLen    int64
+	cmdSpeech bool
+}
+
+//NewCommandPipeline adds the keyword search configured by opts.WakeWord to p and starts listening. p must not be used otherwise until the pipeline is closed.
+func NewCommandPipeline(p *PocketSphinx, opts PipelineOptions) (*CommandPipeline, error) {
+	if opts.CommandSearch == "" {
+		return nil, errors.New("pipeline error:no command search")
+	}
+	if opts.CommandWindow == 0 {
+		opts.CommandWindow = 5 * time.Second
+	}
+	if opts.PreRoll == 0 {
+		opts.PreRoll = time.Second
+	}
+	if opts.WakeWord.Search == "" {
+		opts.WakeWord.Search = "wakeword"
+	}
+	wake, err := NewWakeWord(p, opts.WakeWord)
+	if err != nil {
+		return nil, err
+	}
+	rate := p.sampleRate()
+	c := &CommandPipeline{
+		p:         p,
+		wake:      wake,
+		kwsSearch: opts.WakeWord.Search,
+		cmdSearch: opts.CommandSearch,
+		window:    int64(opts.CommandWindow.Seconds() * rate),
+		preRoll:   int(opts.PreRoll.Seconds() * rate),
+	}
+	c.process = c.ProcessRaw
+	wake.OnDetect(func(det WakeDetection) {
+		if c.detected == nil {
+			c.detected = &det
+		}
+	})
+	return c, nil
+}
+
+//OnWake registers f to be called when the wake word is detected, before the command is listened for. Callbacks run on the goroutine processing audio and must be registered before it starts.
+func (c *CommandPipeline) OnWake(f func(WakeDetection)) {
+	c.onWake = f
+}
+
+//OnCommand registers f to be called with every command.
+func (c *CommandPipeline) OnCommand(f func(Command)) {
+	c.onCommand = f
+}
+
+//ProcessRaw processes a chunk of single channel, 16-bit pcm audio.
+func (c *CommandPipeline) ProcessRaw(raw []int16) error {
+	c.remember(raw)
+	if c.inCommand {
+		return c.listen(raw)
+	}
+	if err := c.wake.ProcessRaw(raw); err != nil {
+		return err
+	}
+	if c.detected == nil {
+		return nil
+	}
+
+	det := *c.detected
+	c.detected = nil
+	if c.onWake != nil {
+		c.onWake(det)
+	}
+	if err := c.p.EndUtt(); err != nil {
+		return err
+	}
+	if err := c.p.SetSearch(c.cmdSearch); err != nil {
+		return err
+	}
+	if err := c.p.StartUtt(); err != nil {
+		return err
+	}
+	c.inCommand = true
+	c.cmd = det
+	c.cmdLen = 0
+	c.cmdSpeech = false
+	return c.listen(c.since(det.End))
+}
+
+//Close ends the current utterance. A command in progress is dropped.
+func (c *CommandPipeline) Close() error {
+	return c.p.EndUtt()
+}
+
+//listen decodes raw with the command search, finishing the command at the end of speech or of the window.
+func (c *CommandPipeline) listen(raw []int16) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	if _, err := c.p.processNative(raw, false, false); err != nil {
+		return err
+	}
+	c.cmdLen += int64(len(raw))
+	inSpeech := c.p.IsInSpeech()
+	if inSpeech {
+		c.cmdSpeech = true
+	}
+	ended := c.cmdSpeech && !inSpeech
+	if !ended && c.cmdLen < c.window {
+		return nil
+	}
+
+	if err := c.p.EndUtt(); err != nil {
+		return err
+	}
+	cmd := Command{Wake: c.cmd, TimedOut: !ended}
+	if hyp, err := c.p.GetHyp(); err == nil {
+		cmd.Result = hyp
+	}
+	c.inCommand = false
+	if err := c.wake.resume(c.kwsSearch, c.total); err != nil {
+		return err
+	}
+	if c.onCommand != nil {
+		c.onCommand(cmd)
+	}
+	return nil
+}
+
+//remember keeps the last preRoll samples of audio.
+func (c *CommandPipeline) remember(raw []int16) {
+	c.total += int64(len(raw))
+	c.history = append(c.history, raw...)
+	if over := len(c.history) - c.preRoll; over > 0 {
+		c.history = c.history[:copy(c.history, c.history[over:])]
+	}
+}
+
+//since gets the remembered audio from time t on.
+func (c *CommandPipeline) since(t time.Duration) []int16 {
+	start := int64(t.Seconds() * c.p.sampleRate())
+	first := c.total - int64(len(c.history))
+	if start < first {
+		start = first
+	}
+	if start > c.total {
+		return nil
+	}
+	return c.history[start-first:]
+}
@@ -1,13 +1,36 @@
 package pocketsphinx
 
 /*
-#cgo pkg-config: pocketsphinx sphinxbase
 #include <pocketsphinx.h>
 #include <err.h>
 #include <stdio.h>
+#include <string.h>
 cmd_ln_t *default_config(){
     return cmd_ln_parse_r(NULL, ps_args(), 0, NULL, FALSE);
 }
+void capture_begin();
+void capture_end(char *errbuf, size_t errlen);
+ps_decoder_t *init_decoder(cmd_ln_t *config, char *errbuf, size_t errlen){
+    ps_decoder_t *ps;
+    capture_begin();
+    ps = ps_init(config);
+    capture_end(errbuf, errlen);
+    return ps;
+}
+int reinit_decoder(ps_decoder_t *ps, cmd_ln_t *config, char *errbuf, size_t errlen){
+    int ret;
+    capture_begin();
+    ret = ps_reinit(ps, config);
+    capture_end(errbuf, errlen);
+    return ret;
+}
+cmd_ln_t *parse_args(int argc, char **argv, char *errbuf, size_t errlen){
+    cmd_ln_t *config;
+    capture_begin();
+    config = cmd_ln_parse_r(NULL, ps_args(), argc, argv, TRUE);
+    capture_end(errbuf, errlen);
+    return config;
+}
 int process_raw(ps_decoder_t *ps, char const *data, size_t n_samples, int no_search, int full_utt){
     n_samples /= sizeof(int16);
     return ps_process_raw(ps, (int16 *)data, n_samples, no_search, full_utt);
@@ -16,52 +39,278 @@ int process_raw(ps_decoder_t *ps, char const *data, size_t n_samples, int no_sea
 import "C"
 
 import (
+	"context"
+	"encoding/binary"
 	"errors"
 	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"sync/atomic"
 	"unsafe"
+
+	"github.com/andyleap/pocketsphinx/decoder"
+)
+
+//ErrNoHypothesis is returned when the decoder has no hypothesis for the utterance, e.g. because it contained no speech.
+var ErrNoHypothesis = decoder.ErrNoHypothesis
+
+//ErrConcurrentUse is returned when a decoder is used by another goroutine at the same time. Decoders are not safe for concurrent use, see Pool.
+var ErrConcurrentUse = errors.New("decoder used concurrently")
+
+//ErrClosed is returned when a decoder is used after Free or Close.
+var ErrClosed = decoder.ErrClosed
+
+//Errors returned when adding or activating searches. They are wrapped with the name of the search and the reason given by sphinx.
+var (
+	ErrBadGrammar    = errors.New("bad grammar")
+	ErrUnknownSearch = decoder.ErrUnknownSearch
+	ErrWordNotInDict = errors.New("word not in dictionary")
+	ErrBadLM         = errors.New("bad language model")
 )
 
+//ErrEmptyInput is returned when an empty buffer of audio is passed for processing.
+var ErrEmptyInput = decoder.ErrEmptyInput
+
+//ErrUnsupported is returned by features the linked pocketsphinx library doesn't provide.
+var ErrUnsupported = errors.New("not supported by this pocketsphinx version")
+
+//ErrSampleRate is returned when audio isn't at the sampling rate of the decoder, or the decoder's sampling rate doesn't suit its acoustic model. Decoding it anyway would produce nonsense transcripts.
+var ErrSampleRate = errors.New("sample rate mismatch")
+
 //Result is a speech recognition result
-type Result struct {
-	Text  string `json:"text"`
-	Score int64  `json:"score"`
-	Prob  int64  `json:"prob"`
-}
+type Result = decoder.Result
+
+//Decoder is the interface implemented by PocketSphinx, so code using it can be tested with decoder.FakeDecoder.
+type Decoder = decoder.Decoder
+
+var _ Decoder = (*PocketSphinx)(nil)
 
 //PocketSphinx is a speech recognition decoder object
 type PocketSphinx struct {
 	ps *C.ps_decoder_t
-}
 
-//NewPocketSphinx creates PocketSphinx instance with specific options.
-func NewPocketSphinx(hmm string, dict string, samprate float64) *PocketSphinx {
-	psConfig := C.default_config()
+	//phoneSearches are the names of searches that produce phone rather than word segmentations.
+	phoneSearches map[string]bool
+
+	//cont tracks the utterance state of ProcessContinuous, nil when no utterance is in progress.
+	cont *uttLoop
+
+	//conv is reused to convert audio in other formats to 16-bit pcm.
+	conv []int16
+	//inputOrder is the byte order of the audio passed to ProcessRaw and ProcessRawBytes when it isn't the machine's, nil otherwise.
+	inputOrder binary.ByteOrder
+
+	//maxChunk is the most samples ProcessRaw passes to the decoder at once, zero for no limit.
+	maxChunk int
+
+	//partial holds the words of the last GetPartialHyp in the utterance.
+	partial []string
+
+	//frate is the number of frames per second (-frate).
+	frate float64
+	//segs is reused by GetHypInto.
+	segs []Segment
+
+	//inUse is set while a method that calls into the decoder is running, to detect concurrent use.
+	inUse int32
+
+	//logID identifies the decoder to the log bridge when it has its own LogHandler, zero otherwise. logLocked is set while the goroutine using the decoder is locked to its thread to route log messages.
+	logID     uintptr
+	logLocked bool
 
-	path := C.CString("/dev/null")
-	defer C.free(unsafe.Pointer(path))
-	C.err_set_logfile(path)
+	//tmpDir holds the model files extracted by NewFromFS, removed by Free.
+	tmpDir string
 
+	//registry is the Registry the decoder was created from, and language its current language code.
+	registry *Registry
+	language string
+}
+
+//NewPocketSphinx creates PocketSphinx instance with specific options. It returns an error if the model paths don't exist or the decoder fails to initialize.
+func NewPocketSphinx(hmm string, dict string, samprate float64) (*PocketSphinx, error) {
+	opts := []Option{WithSampleRate(samprate)}
 	if hmm != "" {
-		setStringParam(psConfig, "-hmm", hmm)
+		opts = append(opts, WithHMM(hmm))
 	}
 	if dict != "" {
-		setStringParam(psConfig, "-dict", dict)
+		opts = append(opts, WithDict(dict))
+	}
+	return New(opts...)
+}
+
+//New creates PocketSphinx instance configured by opts. Flags that aren't set keep the pocketsphinx defaults.
+func New(opts ...Option) (*PocketSphinx, error) {
+	psConfig := C.default_config()
+	for _, opt := range opts {
+		opt(psConfig)
+	}
+	return newDecoder(psConfig)
+}
+
+//NewFromArgs creates PocketSphinx instance from command line style flags, e.g. []string{"-hmm", hmm, "-dict", dict}, as accepted by the pocketsphinx tools. args must not include the program name, and "-argfile" is honored.
+func NewFromArgs(args []string) (*PocketSphinx, error) {
+	argv := make([]*C.char, 0, len(args)+1)
+	argv = append(argv, C.CString("pocketsphinx"))
+	for _, arg := range args {
+		argv = append(argv, C.CString(arg))
+	}
+	defer func() {
+		for _, arg := range argv {
+			C.free(unsafe.Pointer(arg))
+		}
+	}()
+
+	cargv := (**C.char)(C.malloc(C.size_t(len(argv)) * C.size_t(unsafe.Sizeof(argv[0]))))
+	defer C.free(unsafe.Pointer(cargv))
+	copy(unsafe.Slice(cargv, len(argv)), argv)
+
+	var errbuf [512]C.char
+	psConfig := C.parse_args(C.int(len(argv)), cargv, &errbuf[0], C.size_t(len(errbuf)))
+	if psConfig == nil {
+		return nil, fmt.Errorf("parse_args error:%s", errReason(errbuf[:]))
+	}
+	return newDecoder(psConfig)
+}
+
+//newDecoder creates PocketSphinx instance from psConfig and releases psConfig.
+func newDecoder(psConfig *C.cmd_ln_t) (*PocketSphinx, error) {
+	defer C.cmd_ln_free_r(psConfig)
+
+	if err := checkPaths(psConfig); err != nil {
+		return nil, err
+	}
+	logfn := takeLogFile(psConfig)
+	order := takeInputEndian(psConfig)
+
+	ps, err := initDecoder(psConfig)
+	if err != nil {
+		if rerr := modelRateError(psConfig); rerr != nil {
+			return nil, rerr
+		}
+		return nil, err
+	}
+
+	p := &PocketSphinx{ps: ps, inputOrder: order}
+	p.afterInit(psConfig)
+	runtime.SetFinalizer(p, finalize)
+	if logfn != "" {
+		if err := p.setLogFile(logfn); err != nil {
+			p.Free()
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+//pathParams are the flags naming files or directories that must exist before ps_init is called.
+var pathParams = []string{"-hmm", "-dict", "-lm", "-jsgf", "-kws"}
+
+func checkPaths(psConfig *C.cmd_ln_t) error {
+	for _, key := range pathParams {
+		val := getStringParam(psConfig, key)
+		if val == "" {
+			continue
+		}
+		if _, err := os.Stat(val); err != nil {
+			return fmt.Errorf("%s error:%v", key[1:], err)
+		}
+	}
+	return nil
+}
+
+//initDecoder calls ps_init, capturing the last error sphinx logged so it can be returned on failure.
+func initDecoder(psConfig *C.cmd_ln_t) (*C.ps_decoder_t, error) {
+	var errbuf [512]C.char
+	ps := C.init_decoder(psConfig, &errbuf[0], C.size_t(len(errbuf)))
+	if ps == nil {
+		return nil, fmt.Errorf("ps_init error:%s", errReason(errbuf[:]))
 	}
-	setFloatParam(psConfig, "-samprate", samprate)
+	return ps, nil
+}
 
-	ps := C.ps_init(psConfig)
-	C.cmd_ln_free_r(psConfig)
+//errReason extracts the sphinx error message captured in errbuf.
+func errReason(errbuf []C.char) string {
+	reason := strings.TrimSpace(C.GoString(&errbuf[0]))
+	if reason == "" {
+		return "unknown failure"
+	}
+	return reason
+}
 
-	return &PocketSphinx{ps: ps}
+//Reconfigure reinitializes the decoder with cfg, reloading the acoustic model, dictionary and language model as needed. Flags not set in cfg revert to the pocketsphinx defaults. Named searches added at runtime are discarded.
+func (p *PocketSphinx) Reconfigure(cfg Config) error {
+	if err := p.acquire(); err != nil {
+		return err
+	}
+	defer p.release()
+	psConfig := C.default_config()
+	defer C.cmd_ln_free_r(psConfig)
+	for _, opt := range cfg.Options() {
+		opt(psConfig)
+	}
+	if err := checkPaths(psConfig); err != nil {
+		return err
+	}
+	order := takeInputEndian(psConfig)
+	if err := reinitDecoder(p.ps, psConfig); err != nil {
+		return err
+	}
+	p.inputOrder = order
+	p.afterInit(psConfig)
+	return nil
 }
 
-//Free releases all resources associated with the PocketSphinx.
-func (p *PocketSphinx) Free() {
+//reinitDecoder calls ps_reinit, capturing the last error sphinx logged so it can be returned on failure. A nil psConfig reuses the decoder's current configuration.
+func reinitDecoder(ps *C.ps_decoder_t, psConfig *C.cmd_ln_t) error {
+	var errbuf [512]C.char
+	ret := C.reinit_decoder(ps, psConfig, &errbuf[0], C.size_t(len(errbuf)))
+	if ret != 0 {
+		return fmt.Errorf("reinit error:%s", errReason(errbuf[:]))
+	}
+	return nil
+}
+
+//Free releases all resources associated with the PocketSphinx. It is safe to call more than once; afterwards methods return ErrClosed or zero values. While another goroutine is using the decoder, it returns ErrConcurrentUse without freeing it.
+func (p *PocketSphinx) Free() error {
+	if err := p.acquire(); err != nil {
+		if errors.Is(err, ErrClosed) {
+			return nil
+		}
+		return err
+	}
+	runtime.SetFinalizer(p, nil)
 	C.ps_free(p.ps)
+	p.ps = nil
+	p.release()
+	p.resetLog()
+	if p.tmpDir != "" {
+		os.RemoveAll(p.tmpDir)
+		p.tmpDir = ""
+	}
+	return nil
+}
+
+//Close implements io.Closer by calling Free.
+func (p *PocketSphinx) Close() error {
+	return p.Free()
+}
+
+//finalize frees decoders that were garbage collected without being freed, reporting the leak to the package level LogHandler as a warning of the binding itself rather than of sphinx.
+func finalize(p *PocketSphinx) {
+	if holder, _ := logHandler.Load().(logHolder); holder.h != nil {
+		holder.h(LogWarn, "go binding diagnostic: decoder was garbage collected without being freed, call Free when done with it")
+	}
+	p.Free()
 }
 
 //StartUtt starts utterance processing.
 func (p *PocketSphinx) StartStream() error {
+	if err := p.acquire(); err != nil {
+		return err
+	}
+	defer p.release()
+
 	ret := C.ps_start_stream(p.ps)
 	if ret != 0 {
 		return fmt.Errorf("start_stream error:%d", ret)
@@ -71,8 +320,15 @@ func (p *PocketSphinx) StartStream() error {
 
 //StartUtt starts utterance processing.
 func (p *PocketSphinx) StartUtt() error {
+	if err := p.acquire(); err != nil {
+		return err
+	}
+	defer p.release()
+
+	p.partial = p.partial[:0]
 	ret := C.ps_start_utt(p.ps)
 	if ret != 0 {
+		decodeError("start_utt")
 		return fmt.Errorf("start_utt error:%d", ret)
 	}
 	return nil
@@ -80,13 +336,42 @@ func (p *PocketSphinx) StartUtt() error {
 
 //EndUtt ends utterance processing.
 func (p *PocketSphinx) EndUtt() error {
+	if err := p.acquire(); err != nil {
+		return err
+	}
+	defer p.release()
+
 	ret := C.ps_end_utt(p.ps)
 	if ret != 0 {
+		decodeError("end_utt")
 		return fmt.Errorf("end_utt error:%d", ret)
 	}
+	if m := getMetrics(); m != nil {
+		var speech, cpu, wall C.double
+		C.ps_get_utt_time(p.ps, &speech, &cpu, &wall)
+		m.UtteranceDecoded(timing(speech, cpu, wall))
+	}
 	return nil
 }
 
+//acquire marks the decoder in use, failing if it already is.
+func (p *PocketSphinx) acquire() error {
+	if !atomic.CompareAndSwapInt32(&p.inUse, 0, 1) {
+		return ErrConcurrentUse
+	}
+	if p.ps == nil {
+		atomic.StoreInt32(&p.inUse, 0)
+		return ErrClosed
+	}
+	p.logEnter()
+	return nil
+}
+
+func (p *PocketSphinx) release() {
+	p.logExit()
+	atomic.StoreInt32(&p.inUse, 0)
+}
+
 func bool2int(b bool) int {
 	if b {
 		return 1
@@ -95,67 +380,146 @@ func bool2int(b bool) int {
 }
 
 //ProcessRaw processes a single channel, 16-bit pcm signal. if noSearch is true, ProcessRaw performs only feature extraction but don't do any recognition yet. if fullUtt is true, this block of data is a full utterance worth of data.
-func (p *PocketSphinx) ProcessRaw(raw []int16, noSearch, fullUtt bool) error {
-	raw_byte := (*C.char)(unsafe.Pointer(&raw[0]))
-	numByte := len(raw) * 2
-	processed := C.process_raw(p.ps, raw_byte, C.size_t(numByte), C.int(bool2int(noSearch)), C.int(bool2int(fullUtt)))
-	if processed < 0 {
-		return fmt.Errorf("process_raw error")
+//It returns the number of frames searched, which lags behind the audio passed when the search can't keep up with real time.
+//Unless fullUtt is set, buffers longer than the limit set with SetMaxChunk are passed to the decoder in several calls. An empty buffer returns ErrEmptyInput.
+func (p *PocketSphinx) ProcessRaw(raw []int16, noSearch, fullUtt bool) (int, error) {
+	if err := p.acquire(); err != nil {
+		return 0, err
 	}
-	return nil
+	defer p.release()
+	if p.inputOrder != nil {
+		p.conv = append(p.conv[:0], raw...)
+		SwapBytes(p.conv)
+		raw = p.conv
+	}
+	return p.processRaw(raw, noSearch, fullUtt)
 }
 
-//GetHyp gets speech recognition result for best hypothesis.
-func (p *PocketSphinx) GetHyp() (Result, error) {
-	var score C.int32
-	charp := C.ps_get_hyp(p.ps, &score)
-	if charp == nil {
-		return Result{}, errors.New("no hypothesis")
+//processNative is ProcessRaw for audio converted by the binding, which is in the machine's byte order whatever WithInputEndian set.
+func (p *PocketSphinx) processNative(raw []int16, noSearch, fullUtt bool) (int, error) {
+	if err := p.acquire(); err != nil {
+		return 0, err
 	}
-	text := C.GoString(charp)
-	ret := Result{Text: text, Score: int64(score), Prob: int64(C.ps_get_prob(p.ps))}
-	return ret, nil
+	defer p.release()
+	return p.processRaw(raw, noSearch, fullUtt)
 }
 
-func (p *PocketSphinx) getNbestHyp(nbest *C.ps_nbest_t) Result {
-	var score C.int32
-	text := C.GoString(C.ps_nbest_hyp(nbest, &score))
-	ret := Result{Text: text, Score: int64(score)}
-	return ret
+func (p *PocketSphinx) processRaw(raw []int16, noSearch, fullUtt bool) (int, error) {
+	if len(raw) == 0 {
+		return 0, ErrEmptyInput
+	}
+	chunk := len(raw)
+	if p.maxChunk > 0 && !fullUtt {
+		chunk = p.maxChunk
+	}
+	searched := 0
+	for len(raw) > 0 {
+		n := chunk
+		if n > len(raw) {
+			n = len(raw)
+		}
+		raw_byte := (*C.char)(unsafe.Pointer(&raw[0]))
+		numByte := n * 2
+		processed := C.process_raw(p.ps, raw_byte, C.size_t(numByte), C.int(bool2int(noSearch)), C.int(bool2int(fullUtt)))
+		if processed < 0 {
+			decodeError("process_raw")
+			return searched, fmt.Errorf("process_raw error")
+		}
+		searched += int(processed)
+		raw = raw[n:]
+	}
+	return searched, nil
 }
 
-func (p *PocketSphinx) GetNbest(numNbest int) []Result {
-	ret := make([]Result, 0, numNbest)
+//SetMaxChunk limits the number of samples passed to the decoder at once by ProcessRaw, so that huge buffers are processed in bounded steps. Zero, the default, means no limit.
+func (p *PocketSphinx) SetMaxChunk(samples int) {
+	p.maxChunk = samples
+}
 
-	nbestIt := C.ps_nbest(p.ps)
-	for {
-		if nbestIt == nil {
-			break
-		}
-		if len(ret) == numNbest {
-			C.ps_nbest_free(nbestIt)
-			break
-		}
+//ProcessRawBytes is like ProcessRaw, but takes the samples as bytes in the machine's byte order, or the one set with WithInputEndian. They are passed to the decoder without copying unless they must be swapped.
+func (p *PocketSphinx) ProcessRawBytes(data []byte, noSearch, fullUtt bool) (int, error) {
+	if err := p.acquire(); err != nil {
+		return 0, err
+	}
+	defer p.release()
 
-		hyp := p.getNbestHyp(nbestIt)
-		if hyp.Text == "" {
-			C.ps_nbest_free(nbestIt)
-			break
+	if len(data)%2 != 0 {
+		return 0, fmt.Errorf("process_raw error:odd number of bytes %d", len(data))
+	}
+	if len(data) == 0 {
+		return 0, ErrEmptyInput
+	}
+	if p.inputOrder != nil {
+		p.conv = p.conv[:0]
+		for i := 0; i < len(data); i += 2 {
+			p.conv = append(p.conv, int16(p.inputOrder.Uint16(data[i:])))
 		}
-		ret = append(ret, hyp)
-		nbestIt = C.ps_nbest_next(nbestIt)
+		return p.processRaw(p.conv, noSearch, fullUtt)
+	}
+	processed := C.process_raw(p.ps, (*C.char)(unsafe.Pointer(&data[0])), C.size_t(len(data)), C.int(bool2int(noSearch)), C.int(bool2int(fullUtt)))
+	if processed < 0 {
+		decodeError("process_raw")
+		return 0, fmt.Errorf("process_raw error")
 	}
+	return int(processed), nil
+}
+
+//GetHyp gets speech recognition result for best hypothesis, including its words with their timings and posteriors.
+func (p *PocketSphinx) GetHyp() (Result, error) {
+	var ret Result
+	err := p.GetHypInto(&ret)
+	return ret, err
+}
+
+//GetHypInto is like GetHyp, but fills in r, reusing the memory of its Words and its Text if unchanged. Polling partial hypotheses this way doesn't allocate while the hypothesis stays the same.
+func (p *PocketSphinx) GetHypInto(r *Result) error {
+	if err := p.acquire(); err != nil {
+		return err
+	}
+	defer p.release()
 
-	return ret
+	var score C.int32
+	charp := C.ps_get_hyp(p.ps, &score)
+	if charp == nil {
+		return ErrNoHypothesis
+	}
+	prob := C.ps_get_prob(p.ps)
+	r.Text = goStringReuse(charp, r.Text)
+	r.Score = int64(score)
+	r.Prob = int64(prob)
+	r.Confidence = p.logMath().Exp(int64(prob))
+	r.Segments = r.Segments[:0]
+	r.Words = r.Words[:0]
+	if seg := C.ps_seg_iter(p.ps); seg != nil {
+		p.segs = appendSegments(p.segs[:0], seg)
+		r.Words = p.appendWordResults(r.Words, p.segs)
+	}
+	return nil
+}
+
+//goStringReuse converts cs to a Go string, returning prev instead of allocating when they are equal.
+func goStringReuse(cs *C.char, prev string) string {
+	b := unsafe.Slice((*byte)(unsafe.Pointer(cs)), C.strlen(cs))
+	if string(b) == prev {
+		return prev
+	}
+	return string(b)
 }
 
 func (p *PocketSphinx) ProcessUtt(raw []int16, numNbest int) ([]Result, error) {
+	_, span := p.trace(context.Background(), "ProcessUtt")
+	ret, err := p.processUtt(raw, numNbest)
+	span.end(p.samplesDuration(len(raw)), countWords(ret), err)
+	return ret, err
+}
+
+func (p *PocketSphinx) processUtt(raw []int16, numNbest int) ([]Result, error) {
 	ret := make([]Result, 0, numNbest)
 	err := p.StartUtt()
 	if err != nil {
 		return ret, err
 	}
-	err = p.ProcessRaw(raw, false, true)
+	_, err = p.processNative(raw, false, true)
 	if err != nil {
 		return ret, err
 	}
@@ -174,34 +538,152 @@ func (p *PocketSphinx) ProcessUtt(raw []int16, numNbest int) ([]Result, error) {
 	return ret, nil
 }
 
-func (p *PocketSphinx) ParseJSGF(name string, grammar string) {
+//uttChunkSeconds is how much audio ProcessUttContext passes to the decoder between checks of its context.
+const uttChunkSeconds = 0.1
+
+//ProcessUttContext is like ProcessUtt, but passes raw to the decoder in chunks and gives up when ctx is done, ending the utterance and returning ctx.Err().
+func (p *PocketSphinx) ProcessUttContext(ctx context.Context, raw []int16, numNbest int) ([]Result, error) {
+	ctx, span := p.trace(ctx, "ProcessUttContext")
+	ret, err := p.processUttContext(ctx, raw, numNbest)
+	span.end(p.samplesDuration(len(raw)), countWords(ret), err)
+	return ret, err
+}
+
+func (p *PocketSphinx) processUttContext(ctx context.Context, raw []int16, numNbest int) ([]Result, error) {
+	if len(raw) == 0 {
+		return nil, ErrEmptyInput
+	}
+	if err := p.StartUtt(); err != nil {
+		return nil, err
+	}
+	chunk := int(p.sampleRate() * uttChunkSeconds)
+	if chunk <= 0 {
+		chunk = len(raw)
+	}
+	for len(raw) > 0 {
+		if err := ctx.Err(); err != nil {
+			p.EndUtt()
+			return nil, err
+		}
+		n := chunk
+		if n > len(raw) {
+			n = len(raw)
+		}
+		if _, err := p.processNative(raw[:n], false, false); err != nil {
+			p.EndUtt()
+			return nil, err
+		}
+		raw = raw[n:]
+	}
+	if err := p.EndUtt(); err != nil {
+		return nil, err
+	}
+	r, err := p.GetHyp()
+	if err != nil {
+		return nil, err
+	}
+	if numNbest < 1 {
+		numNbest = 1
+	}
+	return append([]Result{r}, p.GetNbest(numNbest-1)...), nil
+}
+
+//ParseJSGF adds a search named name for the JSGF grammar. It returns ErrBadGrammar if the grammar doesn't parse, or ErrWordNotInDict if it uses words missing from the dictionary.
+func (p *PocketSphinx) ParseJSGF(name string, grammar string) error {
+	if err := p.acquire(); err != nil {
+		return err
+	}
+	defer p.release()
 	cname := C.CString(name)
 	cgrammar := C.CString(grammar)
-	C.ps_set_jsgf_string(p.ps, cname, cgrammar)
-	C.free(unsafe.Pointer(cname))
-	C.free(unsafe.Pointer(cgrammar))
+	defer C.free(unsafe.Pointer(cname))
+	defer C.free(unsafe.Pointer(cgrammar))
+	var ret C.int
+	reason := captureErrors(func() {
+		ret = C.ps_set_jsgf_string(p.ps, cname, cgrammar)
+	})
+	if ret != 0 {
+		return searchError("jsgf", name, ErrBadGrammar, reason)
+	}
+	return nil
 }
 
-func (p *PocketSphinx) SetKeyphrase(name string, keyphrase string) {
+//SetKeyphrase adds a keyword spotting search named name for keyphrase. It returns ErrWordNotInDict if the keyphrase uses words missing from the dictionary.
+func (p *PocketSphinx) SetKeyphrase(name string, keyphrase string) error {
+	if err := p.acquire(); err != nil {
+		return err
+	}
+	defer p.release()
 	cname := C.CString(name)
 	ckeyphrase := C.CString(keyphrase)
-	C.ps_set_keyphrase(p.ps, cname, ckeyphrase)
-	C.free(unsafe.Pointer(cname))
-	C.free(unsafe.Pointer(ckeyphrase))
+	defer C.free(unsafe.Pointer(cname))
+	defer C.free(unsafe.Pointer(ckeyphrase))
+	var ret C.int
+	reason := captureErrors(func() {
+		ret = C.ps_set_keyphrase(p.ps, cname, ckeyphrase)
+	})
+	if ret != 0 {
+		return searchError("keyphrase", name, ErrWordNotInDict, reason)
+	}
+	return nil
 }
 
-func (p *PocketSphinx) SetSearch(name string) {
+//SetSearch activates the search named name. It returns ErrUnknownSearch if there's no such search.
+func (p *PocketSphinx) SetSearch(name string) error {
+	if err := p.acquire(); err != nil {
+		return err
+	}
+	defer p.release()
 	cname := C.CString(name)
-	C.ps_set_search(p.ps, cname)
-	C.free(unsafe.Pointer(cname))
+	defer C.free(unsafe.Pointer(cname))
+	if C.ps_set_search(p.ps, cname) != 0 {
+		return fmt.Errorf("set_search %s error:%w", name, ErrUnknownSearch)
+	}
+	return nil
 }
 
+//searchError builds the error for a search that failed to be added. Missing dictionary words are reported as ErrWordNotInDict, other failures as fallback.
+func searchError(kind, name string, fallback error, reason string) error {
+	err := fallback
+	if strings.Contains(reason, "missing in the dictionary") {
+		err = ErrWordNotInDict
+	}
+	if reason == "" {
+		return fmt.Errorf("%s %s error:%w", kind, name, err)
+	}
+	return fmt.Errorf("%s %s error:%w:%s", kind, name, err, reason)
+}
+
+//captureErrors calls f, returning the last error sphinx logged while it ran. Errors are captured by the log callback of the calling thread, so the goroutine stays on it until f returns, and errors logged by other decoders at the same time aren't picked up.
+func captureErrors(f func()) string {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	var errbuf [512]C.char
+	C.capture_begin()
+	f()
+	C.capture_end(&errbuf[0], C.size_t(len(errbuf)))
+	return strings.TrimSpace(C.GoString(&errbuf[0]))
+}
+
+//GetSearch gets the name of the active search.
 func (p *PocketSphinx) GetSearch() string {
-	cname := C.ps_get_search(p.ps)
-	return C.GoString(cname)
+	if p.acquire() != nil {
+		return ""
+	}
+	defer p.release()
+	return p.getSearch()
+}
+
+func (p *PocketSphinx) getSearch() string {
+	return C.GoString(C.ps_get_search(p.ps))
 }
 
+//IsInSpeech reports whether voice activity detection has detected speech in the current utterance. It is false while another goroutine is using the decoder.
 func (p *PocketSphinx) IsInSpeech() bool {
+	if p.acquire() != nil {
+		return false
+	}
+	defer p.release()
 	ret := C.ps_get_in_speech(p.ps)
 	return ret == 1
 }
@@ -214,6 +696,27 @@ func setStringParam(psConfig *C.cmd_ln_t, key, val string) {
 	C.cmd_ln_set_str_r(psConfig, keyPtr, valPtr)
 }
 
+func getStringParam(psConfig *C.cmd_ln_t, key string) string {
+	keyPtr := C.CString(key)
+	defer C.free(unsafe.Pointer(keyPtr))
+	if C.cmd_ln_exists_r(psConfig, keyPtr) == 0 {
+		return ""
+	}
+	return C.GoString(C.cmd_ln_str_r(psConfig, keyPtr))
+}
+
+func getIntParam(psConfig *C.cmd_ln_t, key string) int64 {
+	keyPtr := C.CString(key)
+	defer C.free(unsafe.Pointer(keyPtr))
+	return int64(C.cmd_ln_int_r(psConfig, keyPtr))
+}
+
+func getFloatParam(psConfig *C.cmd_ln_t, key string) float64 {
+	keyPtr := C.CString(key)
+	defer C.free(unsafe.Pointer(keyPtr))
+	return float64(C.cmd_ln_float_r(psConfig, keyPtr))
+}
+
 func setFloatParam(psConfig *C.cmd_ln_t, key string, val float64) {
 	keyPtr := C.CString(key)
 	defer C.free(unsafe.Pointer(keyPtr))
@@ -225,3 +728,7 @@ func setIntParam(psConfig *C.cmd_ln_t, key string, val int64) {
 	defer C.free(unsafe.Pointer(keyPtr))
 	C.cmd_ln_set_int_r(psConfig, keyPtr, C.long(val))
 }
+
+func setBoolParam(psConfig *C.cmd_ln_t, key string, val bool) {
+	setIntParam(psConfig, key, int64(bool2int(val)))
+}
@@ -0,0 +1,136 @@
+package pocketsphinx
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+//ErrPoolClosed is returned by Pool.Get after the pool was closed.
+var ErrPoolClosed = errors.New("pool closed")
+
+//Pool hands out decoders created from one Config to concurrent users. A decoder must only be used by one goroutine at a time, so servers Get one per request and Put it back when done.
+type Pool struct {
+	cfg  Config
+	idle chan *PocketSphinx
+
+	mu       sync.Mutex
+	size     int
+	busy     int
+	replaced int
+	failed   int
+	closed   bool
+}
+
+//PoolStats is a snapshot of the state of a Pool.
+type PoolStats struct {
+	//Size is the number of decoders in the pool.
+	Size int
+	//Busy is the number of decoders handed out.
+	Busy int
+	//Replaced is the number of decoders discarded and recreated.
+	Replaced int
+	//Failed is the number of decoders that couldn't be recreated after being discarded, shrinking the pool.
+	Failed int
+}
+
+//NewPool creates Pool of size decoders configured by cfg.
+func NewPool(cfg Config, size int) (*Pool, error) {
+	pl := &Pool{cfg: cfg, idle: make(chan *PocketSphinx, size)}
+	for i := 0; i < size; i++ {
+		p, err := NewFromConfig(cfg)
+		if err != nil {
+			pl.Close()
+			return nil, err
+		}
+		pl.idle <- p
+		pl.size++
+	}
+	return pl, nil
+}
+
+//Get takes a decoder from the pool, waiting until one is free or ctx is done.
+func (pl *Pool) Get(ctx context.Context) (*PocketSphinx, error) {
+	select {
+	case p, ok := <-pl.idle:
+		if !ok {
+			return nil, ErrPoolClosed
+		}
+		pl.mu.Lock()
+		pl.busy++
+		pl.mu.Unlock()
+		pl.report()
+		return p, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+//Put returns a decoder taken with Get to the pool. An utterance left in progress by ProcessContinuous is ended.
+func (pl *Pool) Put(p *PocketSphinx) {
+	if p.cont != nil {
+		p.EndContinuous()
+	}
+	defer pl.report()
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	pl.busy--
+	if pl.closed {
+		p.Free()
+		pl.size--
+		return
+	}
+	pl.idle <- p
+}
+
+//Discard frees a decoder taken with Get that is in a bad state, for example after a decoding error, and replaces it with a new one.
+func (pl *Pool) Discard(p *PocketSphinx) {
+	p.Free()
+	fresh, err := NewFromConfig(pl.cfg)
+
+	defer pl.report()
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	pl.busy--
+	if err != nil || pl.closed {
+		if fresh != nil {
+			fresh.Free()
+		}
+		if err != nil {
+			pl.failed++
+		}
+		pl.size--
+		return
+	}
+	pl.replaced++
+	pl.idle <- fresh
+}
+
+//Stats gets the current state of the pool.
+func (pl *Pool) Stats() PoolStats {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	return PoolStats{Size: pl.size, Busy: pl.busy, Replaced: pl.replaced, Failed: pl.failed}
+}
+
+//report passes the state of the pool to the registered Metrics.
+func (pl *Pool) report() {
+	if m := getMetrics(); m != nil {
+		m.PoolChanged(pl.Stats())
+	}
+}
+
+//Close frees the idle decoders. Decoders handed out are freed when they are put back.
+func (pl *Pool) Close() {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	if pl.closed {
+		return
+	}
+	pl.closed = true
+	close(pl.idle)
+	for p := range pl.idle {
+		p.Free()
+		pl.size--
+	}
+}
@@ -0,0 +1,105 @@
+//Package prommetrics exports the measurements of pocketsphinx decoders and pools to Prometheus.
+//
+//	c := prommetrics.New("pocketsphinx")
+//	prometheus.MustRegister(c)
+//	pocketsphinx.SetMetrics(c)
+package prommetrics
+
+import (
+	"github.com/andyleap/pocketsphinx"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+//Collector implements pocketsphinx.Metrics and prometheus.Collector.
+type Collector struct {
+	utterances prometheus.Counter
+	speech     prometheus.Counter
+	cpu        prometheus.Counter
+	rtf        prometheus.Histogram
+	errors     *prometheus.CounterVec
+	poolSize   prometheus.Gauge
+	poolBusy   prometheus.Gauge
+}
+
+var _ pocketsphinx.Metrics = (*Collector)(nil)
+
+//New creates Collector with metrics named with namespace.
+func New(namespace string) *Collector {
+	return &Collector{
+		utterances: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "utterances_total",
+			Help:      "Number of utterances decoded.",
+		}),
+		speech: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "speech_seconds_total",
+			Help:      "Seconds of speech decoded.",
+		}),
+		cpu: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cpu_seconds_total",
+			Help:      "CPU seconds spent decoding.",
+		}),
+		rtf: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "utterance_rtf",
+			Help:      "Real-time factor of utterances, wall time per second of speech.",
+			Buckets:   []float64{.05, .1, .2, .3, .5, .75, 1, 1.5, 2, 5},
+		}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "errors_total",
+			Help:      "Number of failed decoder operations.",
+		}, []string{"op"}),
+		poolSize: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "pool_decoders",
+			Help:      "Number of decoders in the pool.",
+		}),
+		poolBusy: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "pool_busy_decoders",
+			Help:      "Number of decoders of the pool in use.",
+		}),
+	}
+}
+
+func (c *Collector) collectors() []prometheus.Collector {
+	return []prometheus.Collector{c.utterances, c.speech, c.cpu, c.rtf, c.errors, c.poolSize, c.poolBusy}
+}
+
+//Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, col := range c.collectors() {
+		col.Describe(ch)
+	}
+}
+
+//Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	for _, col := range c.collectors() {
+		col.Collect(ch)
+	}
+}
+
+//UtteranceDecoded implements pocketsphinx.Metrics.
+func (c *Collector) UtteranceDecoded(t pocketsphinx.Timing) {
+	c.utterances.Inc()
+	c.speech.Add(t.Speech.Seconds())
+	c.cpu.Add(t.CPU.Seconds())
+	if t.Speech > 0 {
+		c.rtf.Observe(t.RTF())
+	}
+}
+
+//DecodeError implements pocketsphinx.Metrics.
+func (c *Collector) DecodeError(op string) {
+	c.errors.WithLabelValues(op).Inc()
+}
+
+//PoolChanged implements pocketsphinx.Metrics. With several pools, the gauges follow the last one that changed.
+func (c *Collector) PoolChanged(s pocketsphinx.PoolStats) {
+	c.poolSize.Set(float64(s.Size))
+	c.poolBusy.Set(float64(s.Busy))
+}
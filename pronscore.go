@@ -0,0 +1,217 @@
+package pocketsphinx
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+//PhoneScore is the pronunciation quality of a phone. GOP is its goodness of pronunciation, the log domain acoustic score per frame of the expected phone minus the one of the phones recognized freely over the same frames, which is at most 0 when they agree. Score maps it to a number between 0 and 1: as returned by PronScore, 1 is a perfect match, and once calibrated with a PronCalibration, 1 is as good as the reference speakers.
+type PhoneScore struct {
+	Phone string  `json:"phone"`
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	GOP   float64 `json:"gop"`
+	Score float64 `json:"score"`
+}
+
+//WordScore is the pronunciation quality of a word, the average of the scores of its phones weighted by their duration.
+type WordScore struct {
+	Word   string       `json:"word"`
+	Start  float64      `json:"start"`
+	End    float64      `json:"end"`
+	Score  float64      `json:"score"`
+	Phones []PhoneScore `json:"phones"`
+}
+
+//pronSearch is the name of the phone recognition search used as reference by PronScore.
+const pronSearch = "_pronscore"
+
+//PronScore scores how well the phrase expected is pronounced in audio, a whole utterance of single channel, 16-bit pcm audio. The phones of the phrase are force aligned as by AlignPhones, and the acoustic score of every phone is compared to the one of unconstrained phone recognition over the same frames, which serves as the reference of what was actually said.
+//
+//Even native speakers rarely match the acoustic model perfectly, and some phones are recognized worse than others, so the raw scores are best calibrated against recordings of reference speakers with PronCalibration.
+//
+//The searches used are removed afterwards and the active search is restored, but like AlignPhones with the legacy API, a word for each phone of the phrase is added to the dictionary the first time it is used, named after the phone with the prefix _ph_. Unlike AlignPhones, PronScore aligns this grammar of phone words with pocketsphinx 5 as well rather than using its alignment search, so scores are comparable between the two APIs.
+//
+//PronScore runs several decoder calls in sequence, each holding the decoder only for its own duration, so it must not be interleaved with other calls on p, e.g. from another goroutine or a running ProcessStream.
+func (p *PocketSphinx) PronScore(expected string, audio []int16) (ret []WordScore, err error) {
+	words := strings.Fields(expected)
+	if err := p.checkTranscript(words); err != nil {
+		return nil, err
+	}
+	phones, phoneWords, err := p.phoneWords(words)
+	if err != nil {
+		return nil, err
+	}
+	forced, err := p.alignSegments(phoneWords, audio)
+	if err != nil {
+		return nil, err
+	}
+	if len(forced) != len(phoneWords) {
+		return nil, fmt.Errorf("pronscore error:%w", ErrNoHypothesis)
+	}
+	if err := p.SetAllphoneFile(pronSearch, ""); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if uerr := p.UnsetSearch(pronSearch); uerr != nil && err == nil {
+			ret, err = nil, fmt.Errorf("pronscore error:%w", uerr)
+		}
+	}()
+	free, err := p.decodeWith(pronSearch, audio)
+	if err != nil {
+		return nil, fmt.Errorf("pronscore error:%w", err)
+	}
+
+	lmath := p.LogMath()
+	frate := p.frameRate()
+	ret = make([]WordScore, len(words))
+	for i, word := range words {
+		ws := WordScore{Word: word, Phones: make([]PhoneScore, len(phones[i]))}
+		for j, phone := range phones[i] {
+			seg := forced[0]
+			forced = forced[1:]
+			n := float64(seg.EndFrame - seg.StartFrame + 1)
+			gop := (float64(seg.Ascr) - freeAscr(free, seg.StartFrame, seg.EndFrame)) / n
+			score := 1.0
+			if gop < 0 {
+				score = lmath.Exp(int64(gop))
+			}
+			ws.Phones[j] = PhoneScore{
+				Phone: phone,
+				Start: float64(seg.StartFrame) / frate,
+				End:   float64(seg.EndFrame+1) / frate,
+				GOP:   gop,
+				Score: score,
+			}
+		}
+		if len(ws.Phones) > 0 {
+			ws.Start = ws.Phones[0].Start
+			ws.End = ws.Phones[len(ws.Phones)-1].End
+		}
+		ws.Score = wordScore(ws.Phones)
+		ret[i] = ws
+	}
+	return ret, nil
+}
+
+//wordScore gets the average of the scores of phones weighted by their duration.
+func wordScore(phones []PhoneScore) float64 {
+	var total, duration float64
+	for _, ph := range phones {
+		total += ph.Score * (ph.End - ph.Start)
+		duration += ph.End - ph.Start
+	}
+	if duration == 0 {
+		return 0
+	}
+	return total / duration
+}
+
+//freeAscr gets the acoustic score of segs between frames sf and ef, counting the segments partly in the range in proportion to their overlap with it.
+func freeAscr(segs []Segment, sf, ef int) float64 {
+	var ascr float64
+	for _, seg := range segs {
+		lo, hi := seg.StartFrame, seg.EndFrame
+		if lo < sf {
+			lo = sf
+		}
+		if hi > ef {
+			hi = ef
+		}
+		if hi < lo {
+			continue
+		}
+		ascr += float64(seg.Ascr) * float64(hi-lo+1) / float64(seg.EndFrame-seg.StartFrame+1)
+	}
+	return ascr
+}
+
+//minGOPStdDev is the least spread assumed for the GOP of a phone, so that phones with few or identical reference samples don't turn small differences into bad scores. It is in the log domain of the decoder, about 0.1 nats per frame with the default -logbase of 1.0001.
+const minGOPStdDev = 1000
+
+//PronCalibration holds the distribution of the GOP of every phone in recordings of reference speakers, e.g. native speakers of the language, as scored by PronScore. Apply then rescores a learner's pronunciation against it, so that a score of 1 means as good as the reference speakers rather than a perfect match of the acoustic model. It can be saved and loaded as JSON.
+//
+//	cal := pocketsphinx.NewPronCalibration()
+//	for _, ref := range references {
+//		scores, err := p.PronScore(ref.Text, ref.Audio)
+//		cal.Add(scores)
+//	}
+//	scores, err := p.PronScore(expected, audio)
+//	cal.Apply(scores)
+type PronCalibration struct {
+	Phones map[string]PhoneGOP `json:"phones"`
+}
+
+//PhoneGOP accumulates the GOP of the reference samples of a phone.
+type PhoneGOP struct {
+	Count int     `json:"count"`
+	Sum   float64 `json:"sum"`
+	SumSq float64 `json:"sum_sq"`
+}
+
+//Mean gets the average GOP of the samples.
+func (g PhoneGOP) Mean() float64 {
+	if g.Count == 0 {
+		return 0
+	}
+	return g.Sum / float64(g.Count)
+}
+
+//StdDev gets the standard deviation of the GOP of the samples.
+func (g PhoneGOP) StdDev() float64 {
+	if g.Count == 0 {
+		return 0
+	}
+	mean := g.Mean()
+	variance := g.SumSq/float64(g.Count) - mean*mean
+	if variance <= 0 {
+		return 0
+	}
+	return math.Sqrt(variance)
+}
+
+//NewPronCalibration creates an empty PronCalibration.
+func NewPronCalibration() *PronCalibration {
+	return &PronCalibration{Phones: make(map[string]PhoneGOP)}
+}
+
+//Add adds the phones of scores, the result of PronScore for a recording of a reference speaker, to the calibration.
+func (c *PronCalibration) Add(scores []WordScore) {
+	if c.Phones == nil {
+		c.Phones = make(map[string]PhoneGOP)
+	}
+	for _, ws := range scores {
+		for _, ph := range ws.Phones {
+			g := c.Phones[ph.Phone]
+			g.Count++
+			g.Sum += ph.GOP
+			g.SumSq += ph.GOP * ph.GOP
+			c.Phones[ph.Phone] = g
+		}
+	}
+}
+
+//Score maps the GOP of phone to a score between 0 and 1 against the reference speakers: 1 at or above their average, falling to 0 with the number of standard deviations below it, as the chance of a reference speaker scoring as low. ok is false if phone has no reference samples.
+func (c *PronCalibration) Score(phone string, gop float64) (score float64, ok bool) {
+	g, ok := c.Phones[phone]
+	if !ok || g.Count == 0 {
+		return 0, false
+	}
+	std := math.Max(g.StdDev(), minGOPStdDev)
+	z := (gop - g.Mean()) / std
+	return math.Min(1, math.Erfc(-z/math.Sqrt2)), true
+}
+
+//Apply rescores the phones of scores, the result of PronScore, against the calibration, then the words from their phones. Phones without reference samples keep their score.
+func (c *PronCalibration) Apply(scores []WordScore) {
+	for i := range scores {
+		phones := scores[i].Phones
+		for j := range phones {
+			if score, ok := c.Score(phones[j].Phone, phones[j].GOP); ok {
+				phones[j].Score = score
+			}
+		}
+		scores[i].Score = wordScore(phones)
+	}
+}
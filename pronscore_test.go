@@ -0,0 +1,64 @@
+package pocketsphinx
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPronCalibration(t *testing.T) {
+	cal := NewPronCalibration()
+	cal.Add([]WordScore{
+		{Word: "hat", Phones: []PhoneScore{{Phone: "HH", GOP: -2000}, {Phone: "AE", GOP: -4000}}},
+		{Word: "had", Phones: []PhoneScore{{Phone: "HH", GOP: -6000}, {Phone: "AE", GOP: -4000}}},
+	})
+	if g := cal.Phones["HH"]; g.Count != 2 || g.Mean() != -4000 || g.StdDev() != 2000 {
+		t.Errorf("HH got count %d, mean %v, std dev %v", g.Count, g.Mean(), g.StdDev())
+	}
+	if g := cal.Phones["AE"]; g.StdDev() != 0 {
+		t.Errorf("AE got std dev %v", g.StdDev())
+	}
+
+	tests := []struct {
+		phone string
+		gop   float64
+		want  float64
+		ok    bool
+	}{
+		{phone: "HH", gop: 0, want: 1, ok: true},
+		{phone: "HH", gop: -4000, want: 1, ok: true},
+		{phone: "HH", gop: -6000, want: math.Erfc(1 / math.Sqrt2), ok: true},
+		{phone: "HH", gop: -8000, want: math.Erfc(2 / math.Sqrt2), ok: true},
+		//Identical samples are spread by minGOPStdDev.
+		{phone: "AE", gop: -5000, want: math.Erfc(1 / math.Sqrt2), ok: true},
+		{phone: "T", gop: -1000, ok: false},
+	}
+	for _, tt := range tests {
+		score, ok := cal.Score(tt.phone, tt.gop)
+		if ok != tt.ok || math.Abs(score-tt.want) > 1e-9 {
+			t.Errorf("Score(%s, %v) got %v %v, want %v %v", tt.phone, tt.gop, score, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestPronCalibrationApply(t *testing.T) {
+	cal := &PronCalibration{}
+	cal.Add([]WordScore{{Phones: []PhoneScore{{Phone: "HH", GOP: -1000}, {Phone: "HH", GOP: -3000}}}})
+	scores := []WordScore{{
+		Word: "hat",
+		Phones: []PhoneScore{
+			{Phone: "HH", Start: 0, End: 0.1, GOP: -2000, Score: 0.2},
+			{Phone: "AE", Start: 0.1, End: 0.4, GOP: -9000, Score: 0.6},
+		},
+	}}
+	cal.Apply(scores)
+	//HH is at the reference average, AE has no reference samples and keeps its score.
+	if got := scores[0].Phones[0].Score; got != 1 {
+		t.Errorf("HH got score %v, want 1", got)
+	}
+	if got := scores[0].Phones[1].Score; got != 0.6 {
+		t.Errorf("AE got score %v, want 0.6", got)
+	}
+	if got, want := scores[0].Score, (1*0.1+0.6*0.3)/0.4; math.Abs(got-want) > 1e-9 {
+		t.Errorf("word got score %v, want %v", got, want)
+	}
+}
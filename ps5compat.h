@@ -0,0 +1,85 @@
+/* Maps the pocketsphinx 0.8/5prealpha and sphinxbase API used by the binding to the pocketsphinx 5 API. It is included
+ * before every C file of the package when building with the pocketsphinx5 tag. */
+#ifndef GO_PS5COMPAT_H
+#define GO_PS5COMPAT_H
+
+#include <stdlib.h>
+#include <string.h>
+#include <pocketsphinx.h>
+#include <pocketsphinx/err.h>
+#include <pocketsphinx/model.h>
+#include <pocketsphinx/lattice.h>
+#include <pocketsphinx/logmath.h>
+
+typedef ps_config_t cmd_ln_t;
+typedef ps_arg_t arg_t;
+
+#ifndef FALSE
+#define FALSE 0
+#endif
+#ifndef TRUE
+#define TRUE 1
+#endif
+
+#define cmd_ln_str_r(config, name) ps_config_str(config, name)
+#define cmd_ln_int_r(config, name) ps_config_int(config, name)
+#define cmd_ln_float_r(config, name) ps_config_float(config, name)
+#define cmd_ln_exists_r(config, name) (ps_config_typeof(config, name) != 0)
+#define cmd_ln_set_str_r(config, name, val) ps_config_set_str(config, name, val)
+#define cmd_ln_set_int_r(config, name, val) ps_config_set_int(config, name, val)
+#define cmd_ln_set_float_r(config, name, val) ps_config_set_float(config, name, val)
+#define cmd_ln_free_r(config) ps_config_free(config)
+#define cmd_ln_parse_r(inout, defn, argc, argv, strict) ps5_parse_args(argc, argv)
+
+#define ps_set_search(ps, name) ps_activate_search(ps, name)
+#define ps_get_search(ps) ps_current_search(ps)
+#define ps_unset_search(ps, name) ps_remove_search(ps, name)
+#define ps_set_lm(ps, name, lm) ps_add_lm(ps, name, lm)
+#define ps_set_lm_file(ps, name, path) ps_add_lm_file(ps, name, path)
+#define ps_set_kws(ps, name, path) ps_add_kws(ps, name, path)
+#define ps_set_keyphrase(ps, name, phrase) ps_add_keyphrase(ps, name, phrase)
+#define ps_set_jsgf_file(ps, name, path) ps_add_jsgf_file(ps, name, path)
+#define ps_set_jsgf_string(ps, name, str) ps_add_jsgf_string(ps, name, str)
+#define ps_set_fsg(ps, name, fsg) ps_add_fsg(ps, name, fsg)
+#define ps_set_allphone(ps, name, lm) ps_add_allphone(ps, name, lm)
+#define ps_set_allphone_file(ps, name, path) ps_add_allphone_file(ps, name, path)
+/* Streams are implicit in pocketsphinx 5. */
+#define ps_start_stream(ps) 0
+
+/* ps5_parse_args sets the "-flag value" pairs following the program name in argv on a new configuration, converting
+ * values to the type of their flag, as cmd_ln_parse_r did. */
+static inline ps_config_t *ps5_parse_args(int argc, char **argv){
+    ps_config_t *config = ps_config_init(NULL);
+    int i, type;
+    if (config == NULL)
+        return NULL;
+    for (i = 1; i + 1 < argc; i += 2) {
+        type = ps_config_typeof(config, argv[i]) & ~ARG_REQUIRED;
+        switch (type) {
+        case ARG_INTEGER:
+            ps_config_set_int(config, argv[i], strtol(argv[i + 1], NULL, 10));
+            break;
+        case ARG_FLOATING:
+            ps_config_set_float(config, argv[i], strtod(argv[i + 1], NULL));
+            break;
+        case ARG_BOOLEAN:
+            ps_config_set_bool(config, argv[i], strcmp(argv[i + 1], "yes") == 0 || strcmp(argv[i + 1], "true") == 0 || strcmp(argv[i + 1], "1") == 0);
+            break;
+        case ARG_STRING:
+            ps_config_set_str(config, argv[i], argv[i + 1]);
+            break;
+        default:
+            E_ERROR("Unknown argument: %s\n", argv[i]);
+            ps_config_free(config);
+            return NULL;
+        }
+    }
+    if (i < argc) {
+        E_ERROR("Missing value for argument %s\n", argv[i]);
+        ps_config_free(config);
+        return NULL;
+    }
+    return config;
+}
+
+#endif
@@ -0,0 +1,62 @@
+package pocketsphinx
+
+/*
+#include <pocketsphinx.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"os"
+	"unsafe"
+)
+
+//SetRawDataSize makes the decoder keep the last samples samples of audio of every utterance, so the audio it actually heard can be retrieved with RawData or saved with DumpUtteranceWAV. Zero stops keeping audio, which is the default.
+func (p *PocketSphinx) SetRawDataSize(samples int) error {
+	if err := p.acquire(); err != nil {
+		return err
+	}
+	defer p.release()
+	if samples < 0 {
+		return fmt.Errorf("rawdata error:negative size %d", samples)
+	}
+	C.ps_set_rawdata_size(p.ps, C.int32(samples))
+	return nil
+}
+
+//RawData gets a copy of the audio kept for the current or last utterance, see SetRawDataSize.
+func (p *PocketSphinx) RawData() ([]int16, error) {
+	if err := p.acquire(); err != nil {
+		return nil, err
+	}
+	defer p.release()
+	var buf *C.int16
+	var size C.int32
+	C.ps_get_rawdata(p.ps, &buf, &size)
+	if buf == nil || size <= 0 {
+		return nil, nil
+	}
+	ret := make([]int16, int(size))
+	copy(ret, unsafe.Slice((*int16)(unsafe.Pointer(buf)), int(size)))
+	return ret, nil
+}
+
+//DumpUtteranceWAV saves the audio kept for the current or last utterance to a WAV file at path, e.g. to debug a misrecognized utterance offline or to collect adaptation data. SetRawDataSize must have been called before the utterance started.
+func (p *PocketSphinx) DumpUtteranceWAV(path string) error {
+	raw, err := p.RawData()
+	if err != nil {
+		return err
+	}
+	if len(raw) == 0 {
+		return fmt.Errorf("rawdata error:%w, see SetRawDataSize", ErrEmptyInput)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("rawdata error:%v", err)
+	}
+	if err := WriteWAV(f, int(p.sampleRate()), raw); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
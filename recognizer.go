@@ -0,0 +1,83 @@
+package pocketsphinx
+
+import (
+	"context"
+	"io"
+)
+
+//Recognizer owns a decoder and transcribes continuous audio utterance by utterance, like pocketsphinx_continuous. Final results are delivered to the callback registered with OnResult.
+type Recognizer struct {
+	p      *PocketSphinx
+	stream *Stream
+}
+
+//NewRecognizer creates Recognizer with a decoder configured by cfg.
+func NewRecognizer(cfg Config) (*Recognizer, error) {
+	p, err := NewFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := NewStream(p, 0)
+	if err != nil {
+		p.Free()
+		return nil, err
+	}
+	return &Recognizer{p: p, stream: stream}, nil
+}
+
+//Decoder gets the decoder owned by the Recognizer, e.g. to add searches. It must not be used to process audio.
+func (r *Recognizer) Decoder() *PocketSphinx {
+	return r.p
+}
+
+//Stream gets the Stream the Recognizer feeds, to register further callbacks or read its Results.
+func (r *Recognizer) Stream() *Stream {
+	return r.stream
+}
+
+//OnResult registers f to be called with the result of every utterance.
+func (r *Recognizer) OnResult(f func(Result)) {
+	r.stream.OnFinalResult(f)
+}
+
+//Write implements io.Writer, taking single channel, little-endian 16-bit pcm audio.
+func (r *Recognizer) Write(data []byte) (int, error) {
+	return r.stream.Write(data)
+}
+
+//Run reads audio from src until it is exhausted or ctx is done, then ends the utterance in progress. The Recognizer can be run again afterwards. It returns ErrClosed once the decoder is freed.
+func (r *Recognizer) Run(ctx context.Context, src io.Reader) error {
+	rate, err := r.p.decoderRate()
+	if err != nil {
+		return err
+	}
+	buf := make([]byte, int(rate/10)*2)
+	for {
+		select {
+		case <-ctx.Done():
+			r.stream.Flush()
+			return ctx.Err()
+		default:
+		}
+		n, err := src.Read(buf)
+		if n > 0 {
+			if _, werr := r.stream.Write(buf[:n]); werr != nil {
+				return werr
+			}
+		}
+		if err == io.EOF {
+			return r.stream.Flush()
+		}
+		if err != nil {
+			r.stream.Flush()
+			return err
+		}
+	}
+}
+
+//Close ends the utterance in progress and frees the decoder.
+func (r *Recognizer) Close() error {
+	err := r.stream.Close()
+	r.p.Free()
+	return err
+}
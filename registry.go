@@ -0,0 +1,110 @@
+package pocketsphinx
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//ErrUnknownLanguage is returned when no language of a Registry matches a language code.
+var ErrUnknownLanguage = errors.New("unknown language")
+
+//Registry maps BCP-47 language codes, such as en-US or fr, to the configurations of their models, so multilingual services can pick the decoder configuration of a session by its language. It is safe for concurrent use.
+type Registry struct {
+	mu    sync.RWMutex
+	langs map[string]Config
+}
+
+//NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{langs: make(map[string]Config)}
+}
+
+//Register sets the configuration of the language code, with at least its acoustic model, dictionary and language model or grammar.
+func (r *Registry) Register(code string, cfg Config) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.langs[strings.ToLower(code)] = cfg
+}
+
+//Languages gets the registered language codes, in sorted order and lower case.
+func (r *Registry) Languages() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ret := make([]string, 0, len(r.langs))
+	for code := range r.langs {
+		ret = append(ret, code)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+//Lookup gets the configuration for the language code, and the registered code it matched. Codes match regardless of case, and when the code isn't registered, its subtags are dropped from the end until one is, e.g. en-GB matches en. Failing that, any registered code of the same primary language matches, e.g. en matches en-us.
+func (r *Registry) Lookup(code string) (Config, string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	tag := strings.ToLower(strings.ReplaceAll(code, "_", "-"))
+	for t := tag; t != ""; {
+		if cfg, ok := r.langs[t]; ok {
+			return cfg, t, nil
+		}
+		i := strings.LastIndexByte(t, '-')
+		if i < 0 {
+			break
+		}
+		t = t[:i]
+	}
+	primary, _, _ := strings.Cut(tag, "-")
+	var matches []string
+	for registered := range r.langs {
+		if p, _, _ := strings.Cut(registered, "-"); p == primary {
+			matches = append(matches, registered)
+		}
+	}
+	if len(matches) == 0 {
+		return Config{}, "", fmt.Errorf("language %s error:%w", code, ErrUnknownLanguage)
+	}
+	sort.Strings(matches)
+	return r.langs[matches[0]], matches[0], nil
+}
+
+//NewDecoder creates PocketSphinx instance for the language code, which can later switch languages with SwitchLanguage.
+func (r *Registry) NewDecoder(code string) (*PocketSphinx, error) {
+	cfg, matched, err := r.Lookup(code)
+	if err != nil {
+		return nil, err
+	}
+	p, err := NewFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	p.registry = r
+	p.language = matched
+	return p, nil
+}
+
+//Language gets the registered language code of the decoder, if it was created by Registry.NewDecoder.
+func (p *PocketSphinx) Language() string {
+	return p.language
+}
+
+//SwitchLanguage reinitializes a decoder created by Registry.NewDecoder with the configuration of the language code, as Reconfigure does. Nothing is reloaded if it matches the current language. Named searches added at runtime are discarded.
+func (p *PocketSphinx) SwitchLanguage(code string) error {
+	if p.registry == nil {
+		return errors.New("language error:decoder not created from a Registry")
+	}
+	cfg, matched, err := p.registry.Lookup(code)
+	if err != nil {
+		return err
+	}
+	if matched == p.language {
+		return nil
+	}
+	if err := p.Reconfigure(cfg); err != nil {
+		return err
+	}
+	p.language = matched
+	return nil
+}
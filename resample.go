@@ -0,0 +1,115 @@
+package pocketsphinx
+
+import (
+	"fmt"
+	"math"
+)
+
+//resampleZeros is the number of zero crossings of the interpolation filter on each side, trading quality for speed.
+const resampleZeros = 8
+
+//Resampler converts a stream of 16-bit pcm audio from one sampling rate to another with band limited interpolation, e.g. 44.1kHz or 48kHz sound card input to the 16kHz or 8kHz a model expects. When downsampling, frequencies above the new Nyquist rate are filtered out.
+type Resampler struct {
+	step   float64
+	cutoff float64
+	half   int
+
+	buf []float64
+	pos float64
+	out []int16
+}
+
+//NewResampler creates Resampler from sampling rate from to sampling rate to. It panics if either rate isn't positive.
+func NewResampler(from, to int) *Resampler {
+	if from <= 0 || to <= 0 {
+		panic(fmt.Sprintf("pocketsphinx: invalid resampling from %dHz to %dHz, rates must be positive", from, to))
+	}
+	r := &Resampler{step: float64(from) / float64(to), cutoff: 1}
+	if to < from {
+		r.cutoff = float64(to) / float64(from)
+	}
+	r.half = int(math.Ceil(resampleZeros / r.cutoff))
+	r.buf = make([]float64, r.half)
+	r.pos = float64(r.half)
+	return r
+}
+
+//Process resamples the next chunk of audio. Output is delayed by the length of the filter, call Flush at the end of the stream for the rest. The returned slice is only valid until the next call.
+func (r *Resampler) Process(in []int16) []int16 {
+	for _, s := range in {
+		r.buf = append(r.buf, float64(s))
+	}
+	return r.drain()
+}
+
+//Flush returns the remaining audio at the end of the stream and resets the Resampler.
+func (r *Resampler) Flush() []int16 {
+	r.buf = append(r.buf, make([]float64, r.half)...)
+	out := r.drain()
+	r.buf = make([]float64, r.half)
+	r.pos = float64(r.half)
+	return out
+}
+
+func (r *Resampler) drain() []int16 {
+	r.out = r.out[:0]
+	for int(r.pos)+r.half < len(r.buf) {
+		r.out = append(r.out, clip16(r.interpolate(r.pos)))
+		r.pos += r.step
+	}
+	if drop := int(r.pos) - r.half; drop > 0 {
+		n := copy(r.buf, r.buf[drop:])
+		r.buf = r.buf[:n]
+		r.pos -= float64(drop)
+	}
+	return r.out
+}
+
+//interpolate computes the signal at fractional position t of buf with a Blackman windowed sinc filter.
+func (r *Resampler) interpolate(t float64) float64 {
+	center := int(t)
+	var sum float64
+	for k := center - r.half + 1; k <= center+r.half; k++ {
+		if k < 0 || k >= len(r.buf) {
+			continue
+		}
+		x := t - float64(k)
+		sum += r.buf[k] * r.kernel(x)
+	}
+	return sum
+}
+
+func (r *Resampler) kernel(x float64) float64 {
+	half := float64(r.half)
+	if x <= -half || x >= half {
+		return 0
+	}
+	w := 0.42 + 0.5*math.Cos(math.Pi*x/half) + 0.08*math.Cos(2*math.Pi*x/half)
+	y := r.cutoff * x
+	if y == 0 {
+		return r.cutoff * w
+	}
+	return r.cutoff * math.Sin(math.Pi*y) / (math.Pi * y) * w
+}
+
+//clip16 rounds v to the nearest 16-bit sample, saturating at the limits.
+func clip16(v float64) int16 {
+	v = math.Round(v)
+	if v > math.MaxInt16 {
+		return math.MaxInt16
+	}
+	if v < math.MinInt16 {
+		return math.MinInt16
+	}
+	return int16(v)
+}
+
+//Resample converts a whole recording from sampling rate from to sampling rate to. Like NewResampler, it panics if the rates differ and either isn't positive.
+func Resample(in []int16, from, to int) []int16 {
+	if from == to {
+		return append([]int16(nil), in...)
+	}
+	r := NewResampler(from, to)
+	out := append([]int16(nil), r.Process(in)...)
+	return append(out, r.Flush()...)
+}
@@ -0,0 +1,116 @@
+package pocketsphinx
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func sine(rate int, freq, amplitude float64, n int) []int16 {
+	s := make([]int16, n)
+	for i := range s {
+		s[i] = int16(amplitude * math.Sin(2*math.Pi*freq*float64(i)/float64(rate)))
+	}
+	return s
+}
+
+//rms gets the RMS level of the middle half of s, away from the edges of the filter.
+func rms(s []int16) float64 {
+	s = s[len(s)/4 : 3*len(s)/4]
+	var sum float64
+	for _, v := range s {
+		sum += float64(v) * float64(v)
+	}
+	return math.Sqrt(sum / float64(len(s)))
+}
+
+func TestResample(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to int
+		freq     float64
+		//gain is the expected ratio of the output to the input level.
+		gain float64
+	}{
+		{name: "48k to 16k passband", from: 48000, to: 16000, freq: 1000, gain: 1},
+		{name: "44.1k to 16k passband", from: 44100, to: 16000, freq: 1000, gain: 1},
+		{name: "8k to 16k passband", from: 8000, to: 16000, freq: 1000, gain: 1},
+		{name: "48k to 8k stopband", from: 48000, to: 8000, freq: 7000, gain: 0},
+		{name: "same rate", from: 16000, to: 16000, freq: 1000, gain: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := sine(tt.from, tt.freq, 10000, tt.from/2)
+			out := Resample(in, tt.from, tt.to)
+			if want := len(in) * tt.to / tt.from; math.Abs(float64(len(out)-want)) > 1 {
+				t.Errorf("got %d samples, want %d", len(out), want)
+			}
+			if gain := rms(out) / rms(in); math.Abs(gain-tt.gain) > 0.02 {
+				t.Errorf("got gain %.3f, want %.3f", gain, tt.gain)
+			}
+		})
+	}
+}
+
+func TestResamplerChunks(t *testing.T) {
+	in := sine(44100, 440, 20000, 44100/4)
+	want := Resample(in, 44100, 16000)
+
+	r := NewResampler(44100, 16000)
+	var got []int16
+	for i := 0; i < len(in); i += 441 {
+		end := i + 441
+		if end > len(in) {
+			end = len(in)
+		}
+		got = append(got, r.Process(in[i:end])...)
+	}
+	got = append(got, r.Flush()...)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("chunked output differs from whole, %d and %d samples", len(got), len(want))
+	}
+
+	//Flush resets the Resampler for the next stream.
+	again := append(append([]int16(nil), r.Process(in)...), r.Flush()...)
+	if !reflect.DeepEqual(again, want) {
+		t.Error("output after Flush differs")
+	}
+}
+
+func TestNewResamplerInvalidRates(t *testing.T) {
+	tests := []struct {
+		from, to int
+	}{
+		{16000, 0},
+		{0, 16000},
+		{-8000, 16000},
+		{16000, -8000},
+	}
+	for _, tt := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewResampler(%d, %d) didn't panic", tt.from, tt.to)
+				}
+			}()
+			NewResampler(tt.from, tt.to)
+		}()
+	}
+}
+
+func TestClip16(t *testing.T) {
+	tests := []struct {
+		v    float64
+		want int16
+	}{
+		{0.4, 0},
+		{-0.6, -1},
+		{1e6, math.MaxInt16},
+		{-1e6, math.MinInt16},
+	}
+	for _, tt := range tests {
+		if got := clip16(tt.v); got != tt.want {
+			t.Errorf("clip16(%v) got %d, want %d", tt.v, got, tt.want)
+		}
+	}
+}
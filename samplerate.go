@@ -0,0 +1,57 @@
+package pocketsphinx
+
+/*
+#include <pocketsphinx.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+)
+
+//CheckSampleRate checks that audio at sampleRate can be decoded by p, returning an error wrapping ErrSampleRate that describes the mismatch otherwise. Audio at another rate can be converted with Resample or a Resampler.
+func (p *PocketSphinx) CheckSampleRate(sampleRate float64) error {
+	if err := p.acquire(); err != nil {
+		return err
+	}
+	defer p.release()
+	if rate := p.sampleRate(); sampleRate != rate {
+		return fmt.Errorf("samprate error:%w:audio at %gHz, decoder -samprate is %gHz", ErrSampleRate, sampleRate, rate)
+	}
+	return nil
+}
+
+//decoderRate gets the sampling rate the decoder expects while holding it, so it fails with ErrClosed rather than reading a freed decoder, and errors if the rate isn't positive.
+func (p *PocketSphinx) decoderRate() (float64, error) {
+	if err := p.acquire(); err != nil {
+		return 0, err
+	}
+	defer p.release()
+	rate := p.sampleRate()
+	if rate <= 0 {
+		return 0, fmt.Errorf("samprate error:invalid decoder -samprate %g", rate)
+	}
+	return rate, nil
+}
+
+//modelRateError explains why a decoder failed to initialize when its sampling rate is too low for the filter bank of its acoustic model, e.g. 8kHz with a 16kHz model, nil otherwise.
+func modelRateError(psConfig *C.cmd_ln_t) error {
+	hmm := getStringParam(psConfig, "-hmm")
+	if hmm == "" {
+		return nil
+	}
+	params := make(map[string]string)
+	if err := readFeatParams(filepath.Join(hmm, "feat.params"), params); err != nil {
+		return nil
+	}
+	upperf, err := strconv.ParseFloat(params["upperf"], 64)
+	if err != nil {
+		return nil
+	}
+	if rate := getFloatParam(psConfig, "-samprate"); upperf > rate/2 {
+		return fmt.Errorf("samprate error:%w:decoder -samprate is %gHz, model %s needs at least %gHz audio (-upperf %g)", ErrSampleRate, rate, hmm, 2*upperf, upperf)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package pocketsphinx
+
+/*
+#include <pocketsphinx.h>
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"unsafe"
+)
+
+//SetJSGFFile adds a search named name for the JSGF grammar in the file at path. Grammars imported by it are looked up relative to its directory. Errors are reported as by ParseJSGF.
+func (p *PocketSphinx) SetJSGFFile(name, path string) error {
+	if err := p.acquire(); err != nil {
+		return err
+	}
+	defer p.release()
+	if _, err := os.Stat(path); err != nil {
+		return err
+	}
+	cname := C.CString(name)
+	cpath := C.CString(path)
+	defer C.free(unsafe.Pointer(cname))
+	defer C.free(unsafe.Pointer(cpath))
+	var ret C.int
+	reason := captureErrors(func() {
+		ret = C.ps_set_jsgf_file(p.ps, cname, cpath)
+	})
+	if ret != 0 {
+		return searchError("jsgf", name, ErrBadGrammar, reason)
+	}
+	return nil
+}
+
+//Searches gets the names of all searches, in sorted order.
+func (p *PocketSphinx) Searches() []string {
+	if p.acquire() != nil {
+		return nil
+	}
+	defer p.release()
+	var ret []string
+	for it := C.ps_search_iter(p.ps); it != nil; it = C.ps_search_iter_next(it) {
+		ret = append(ret, C.GoString(C.ps_search_iter_val(it)))
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+//HasSearch reports whether a search named name exists.
+func (p *PocketSphinx) HasSearch(name string) bool {
+	for _, search := range p.Searches() {
+		if search == name {
+			return true
+		}
+	}
+	return false
+}
+
+//UnsetSearch removes the search named name, freeing its grammar or model. The active search can't be removed, switch to another one first.
+func (p *PocketSphinx) UnsetSearch(name string) error {
+	if err := p.acquire(); err != nil {
+		return err
+	}
+	defer p.release()
+	if name == p.getSearch() {
+		return fmt.Errorf("unset_search %s error:search is active", name)
+	}
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	if C.ps_unset_search(p.ps, cname) != 0 {
+		return fmt.Errorf("unset_search %s error:%w", name, ErrUnknownSearch)
+	}
+	delete(p.phoneSearches, name)
+	return nil
+}
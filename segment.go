@@ -0,0 +1,148 @@
+package pocketsphinx
+
+/*
+#include <pocketsphinx.h>
+*/
+import "C"
+
+import (
+	"fmt"
+
+	"github.com/andyleap/pocketsphinx/decoder"
+)
+
+//Segment is a word of a hypothesis with its position in the utterance, in frames, and its scores.
+//Ascr is the acoustic score and Lscr the language model score, already scaled by -lw and including the -wip penalty. Lback is the n-gram order used for the LM score, lower than the model order when it backed off.
+type Segment = decoder.Segment
+
+//WordResult is a recognized word with its start and end time in seconds and its posterior probability between 0 and 1. The posterior is only computed if bestpath search is enabled, otherwise it is 1. Ascr and Lscr are the log domain acoustic and language model scores, as in Segment.
+type WordResult = decoder.WordResult
+
+//PhoneSegment is a context independent phone with its position in the utterance, in frames.
+type PhoneSegment struct {
+	Phone      string `json:"phone"`
+	StartFrame int    `json:"start_frame"`
+	EndFrame   int    `json:"end_frame"`
+	Ascr       int64  `json:"ascr"`
+}
+
+//defaultSearch is the name pocketsphinx gives the search configured by -lm, -jsgf, -kws, -allphone etc.
+const defaultSearch = "_default"
+
+//Segments gets the word segmentation of the best hypothesis. Prob is the log posterior probability of the word, which is only computed if bestpath search is enabled.
+func (p *PocketSphinx) Segments() ([]Segment, error) {
+	if err := p.acquire(); err != nil {
+		return nil, err
+	}
+	defer p.release()
+	return p.segments()
+}
+
+func (p *PocketSphinx) segments() ([]Segment, error) {
+	seg := C.ps_seg_iter(p.ps)
+	if seg == nil {
+		return nil, ErrNoHypothesis
+	}
+	return collectSegments(seg), nil
+}
+
+//collectSegments walks seg to the end, which also frees it.
+func collectSegments(seg *C.ps_seg_t) []Segment {
+	return appendSegments(nil, seg)
+}
+
+//appendSegments is like collectSegments, but appends to dst. Words equal to the ones in the spare capacity of dst are reused rather than allocated again.
+func appendSegments(dst []Segment, seg *C.ps_seg_t) []Segment {
+	for ; seg != nil; seg = C.ps_seg_next(seg) {
+		var prev string
+		if len(dst) < cap(dst) {
+			prev = dst[:len(dst)+1][len(dst)].Word
+		}
+		dst = append(dst, getSegment(seg, prev))
+	}
+	return dst
+}
+
+func getSegment(seg *C.ps_seg_t, prevWord string) Segment {
+	var sf, ef C.int
+	var ascr, lscr, lback C.int32
+	C.ps_seg_frames(seg, &sf, &ef)
+	prob := C.ps_seg_prob(seg, &ascr, &lscr, &lback)
+	return Segment{
+		Word:       goStringReuse(C.ps_seg_word(seg), prevWord),
+		StartFrame: int(sf),
+		EndFrame:   int(ef),
+		Ascr:       int64(ascr),
+		Lscr:       int64(lscr),
+		Lback:      int(lback),
+		Prob:       int64(prob),
+	}
+}
+
+//PhoneSegments gets the phone segmentation of the best hypothesis. Only phone level searches, such as the one configured by -allphone, produce phones, for other searches an error is returned.
+func (p *PocketSphinx) PhoneSegments() ([]PhoneSegment, error) {
+	if err := p.acquire(); err != nil {
+		return nil, err
+	}
+	defer p.release()
+	search := p.getSearch()
+	if !p.phoneSearches[search] {
+		return nil, fmt.Errorf("search %s error:no phone segmentation", search)
+	}
+	segs, err := p.segments()
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]PhoneSegment, 0, len(segs))
+	for _, seg := range segs {
+		ret = append(ret, PhoneSegment{
+			Phone:      seg.Word,
+			StartFrame: seg.StartFrame,
+			EndFrame:   seg.EndFrame,
+			Ascr:       seg.Ascr,
+		})
+	}
+	return ret, nil
+}
+
+//afterInit forgets the searches registered at runtime and caches configuration, after the decoder was (re)initialized from psConfig.
+func (p *PocketSphinx) afterInit(psConfig *C.cmd_ln_t) {
+	p.frate = float64(getIntParam(psConfig, "-frate"))
+	p.phoneSearches = make(map[string]bool)
+	if getStringParam(psConfig, "-allphone") != "" {
+		p.phoneSearches[defaultSearch] = true
+	}
+}
+
+//appendWordResults converts segs to WordResults appended to dst, leaving out fillers.
+func (p *PocketSphinx) appendWordResults(dst []WordResult, segs []Segment) []WordResult {
+	lmath := p.logMath()
+	frate := p.frameRate()
+	for _, seg := range segs {
+		if isFiller(seg.Word) {
+			continue
+		}
+		dst = append(dst, WordResult{
+			Word:  seg.Word,
+			Start: float64(seg.StartFrame) / frate,
+			End:   float64(seg.EndFrame+1) / frate,
+			Prob:  lmath.Exp(seg.Prob),
+			Ascr:  seg.Ascr,
+			Lscr:  seg.Lscr,
+		})
+	}
+	return dst
+}
+
+//frameRate gets the number of frames per second (-frate).
+func (p *PocketSphinx) frameRate() float64 {
+	return p.frate
+}
+
+//sampleRate gets the sampling rate the decoder expects (-samprate).
+func (p *PocketSphinx) sampleRate() float64 {
+	if p.ps == nil {
+		return 0
+	}
+	return getFloatParam(C.ps_get_config(p.ps), "-samprate")
+}
@@ -0,0 +1,213 @@
+package pocketsphinx
+
+import (
+	"encoding/binary"
+	"io"
+	"time"
+)
+
+//SegmenterOptions configures a Segmenter. Zero fields take the defaults noted on each.
+type SegmenterOptions struct {
+	//SampleRate is the sampling rate of the audio. It defaults to 16000.
+	SampleRate int
+	//FrameLength is the duration over which the level is measured. It defaults to 30ms.
+	FrameLength time.Duration
+	//Threshold is the level in dBFS above which a frame counts as speech. It defaults to -40.
+	Threshold float64
+	//MinSilence is how long silence must last to end a segment. It defaults to 500ms.
+	MinSilence time.Duration
+	//MinSpeech is how much speech a segment must contain to be kept. It defaults to 250ms.
+	MinSpeech time.Duration
+	//Padding is how much audio is kept before and after the speech in a segment. It defaults to 200ms.
+	Padding time.Duration
+}
+
+//SpeechSegment is a stretch of speech in a recording, with its offsets from the start of the recording.
+type SpeechSegment struct {
+	Start   time.Duration
+	End     time.Duration
+	Samples []int16
+}
+
+//Segmenter splits a long recording into segments of speech separated by silence, so that it can be decoded utterance by utterance with accurate offsets.
+type Segmenter struct {
+	sampleRate int
+	frameSize  int
+	threshold  float64
+	minSilence int
+	minSpeech  int
+	padding    int
+
+	frame   []int16
+	offset  int
+	preroll []int16
+
+	inSeg       bool
+	segStart    int
+	samples     []int16
+	speechRun   int
+	silenceRun  int
+	segmentsOut []SpeechSegment
+}
+
+//NewSegmenter creates Segmenter configured by opts.
+func NewSegmenter(opts SegmenterOptions) *Segmenter {
+	if opts.SampleRate <= 0 {
+		opts.SampleRate = 16000
+	}
+	if opts.FrameLength <= 0 {
+		opts.FrameLength = 30 * time.Millisecond
+	}
+	if opts.Threshold == 0 {
+		opts.Threshold = -40
+	}
+	if opts.MinSilence <= 0 {
+		opts.MinSilence = 500 * time.Millisecond
+	}
+	if opts.MinSpeech <= 0 {
+		opts.MinSpeech = 250 * time.Millisecond
+	}
+	if opts.Padding <= 0 {
+		opts.Padding = 200 * time.Millisecond
+	}
+	frames := func(d time.Duration) int {
+		n := int(d / opts.FrameLength)
+		if n < 1 {
+			n = 1
+		}
+		return n
+	}
+	//A frame shorter than a sample would never fill, leaving Process looping forever.
+	frameSize := int(float64(opts.SampleRate) * opts.FrameLength.Seconds())
+	if frameSize < 1 {
+		frameSize = 1
+	}
+	return &Segmenter{
+		sampleRate: opts.SampleRate,
+		frameSize:  frameSize,
+		threshold:  opts.Threshold,
+		minSilence: frames(opts.MinSilence),
+		minSpeech:  frames(opts.MinSpeech),
+		padding:    frames(opts.Padding),
+	}
+}
+
+//Process takes the next chunk of single channel, 16-bit pcm audio, of any length, and returns the segments that ended in it.
+func (s *Segmenter) Process(raw []int16) []SpeechSegment {
+	s.segmentsOut = nil
+	for len(raw) > 0 {
+		n := s.frameSize - len(s.frame)
+		if n > len(raw) {
+			n = len(raw)
+		}
+		s.frame = append(s.frame, raw[:n]...)
+		raw = raw[n:]
+		if len(s.frame) == s.frameSize {
+			s.processFrame(s.frame)
+			s.frame = s.frame[:0]
+		}
+	}
+	return s.segmentsOut
+}
+
+//Flush ends the recording, returning the segment in progress if it contains enough speech.
+func (s *Segmenter) Flush() []SpeechSegment {
+	s.segmentsOut = nil
+	if len(s.frame) > 0 {
+		s.processFrame(s.frame)
+		s.frame = s.frame[:0]
+	}
+	if s.inSeg {
+		s.endSegment()
+	}
+	return s.segmentsOut
+}
+
+func (s *Segmenter) processFrame(frame []int16) {
+	speech := level(frame) > s.threshold
+	if !s.inSeg {
+		if speech {
+			s.inSeg = true
+			s.segStart = s.offset - len(s.preroll)
+			s.samples = append(append(s.samples[:0], s.preroll...), frame...)
+			s.speechRun = 1
+			s.silenceRun = 0
+		} else {
+			s.preroll = append(s.preroll, frame...)
+			if limit := s.padding * s.frameSize; len(s.preroll) > limit {
+				s.preroll = append(s.preroll[:0], s.preroll[len(s.preroll)-limit:]...)
+			}
+		}
+		s.offset += len(frame)
+		return
+	}
+
+	s.samples = append(s.samples, frame...)
+	s.offset += len(frame)
+	if speech {
+		s.speechRun++
+		s.silenceRun = 0
+		return
+	}
+	s.silenceRun++
+	if s.silenceRun >= s.minSilence {
+		s.endSegment()
+	}
+}
+
+//endSegment emits the segment in progress, trimming trailing silence beyond the padding.
+func (s *Segmenter) endSegment() {
+	samples := s.samples
+	if extra := (s.silenceRun - s.padding) * s.frameSize; extra > 0 && extra < len(samples) {
+		samples = samples[:len(samples)-extra]
+	}
+	if s.speechRun >= s.minSpeech {
+		s.segmentsOut = append(s.segmentsOut, SpeechSegment{
+			Start:   s.duration(s.segStart),
+			End:     s.duration(s.segStart + len(samples)),
+			Samples: append([]int16(nil), samples...),
+		})
+	}
+	s.inSeg = false
+	s.preroll = s.preroll[:0]
+	s.samples = s.samples[:0]
+}
+
+func (s *Segmenter) duration(samples int) time.Duration {
+	return time.Duration(samples) * time.Second / time.Duration(s.sampleRate)
+}
+
+//SegmentReader reads single channel, little-endian 16-bit pcm audio from r until it is exhausted and calls fn with every segment of speech. It stops at the first error returned by fn.
+func SegmentReader(r io.Reader, opts SegmenterOptions, fn func(SpeechSegment) error) error {
+	s := NewSegmenter(opts)
+	buf := make([]byte, 8192)
+	samples := make([]int16, 0, len(buf)/2)
+	var pending []byte
+	for {
+		n, err := r.Read(buf)
+		data := append(pending, buf[:n]...)
+		samples = samples[:0]
+		for len(data) >= 2 {
+			samples = append(samples, int16(binary.LittleEndian.Uint16(data)))
+			data = data[2:]
+		}
+		pending = append(pending[:0], data...)
+		for _, seg := range s.Process(samples) {
+			if ferr := fn(seg); ferr != nil {
+				return ferr
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for _, seg := range s.Flush() {
+		if err := fn(seg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package pocketsphinx
+
+import (
+	"encoding/binary"
+)
+
+//AudioSink is an io.Writer passing single channel, little-endian 16-bit pcm audio to a decoder, so audio can be copied into it with io.Copy. Utterances are started and ended by the caller as with ProcessRaw.
+type AudioSink struct {
+	pcmWriter
+}
+
+//NewAudioSink creates AudioSink writing to p.
+func NewAudioSink(p *PocketSphinx) *AudioSink {
+	return &AudioSink{pcmWriter{process: func(raw []int16) error {
+		_, err := p.processNative(raw, false, false)
+		return err
+	}}}
+}
+
+//pcmWriter converts little-endian 16-bit pcm bytes to samples, keeping the odd trailing byte of a write for the next one.
+type pcmWriter struct {
+	process func(raw []int16) error
+
+	pending    byte
+	hasPending bool
+	samples    []int16
+}
+
+//Write implements io.Writer.
+func (w *pcmWriter) Write(data []byte) (int, error) {
+	n := len(data)
+	if n == 0 {
+		return 0, nil
+	}
+
+	w.samples = w.samples[:0]
+	if w.hasPending {
+		w.samples = append(w.samples, int16(uint16(w.pending)|uint16(data[0])<<8))
+		data = data[1:]
+		w.hasPending = false
+	}
+	for len(data) >= 2 {
+		w.samples = append(w.samples, int16(binary.LittleEndian.Uint16(data)))
+		data = data[2:]
+	}
+	if len(data) == 1 {
+		w.pending = data[0]
+		w.hasPending = true
+	}
+
+	if len(w.samples) > 0 {
+		if err := w.process(w.samples); err != nil {
+			return 0, err
+		}
+	}
+	return n, nil
+}
+
+//appendSamples appends the little-endian 16-bit samples in data to dst, ignoring an odd trailing byte.
+func appendSamples(dst []int16, data []byte) []int16 {
+	for len(data) >= 2 {
+		dst = append(dst, int16(binary.LittleEndian.Uint16(data)))
+		data = data[2:]
+	}
+	return dst
+}
@@ -0,0 +1,375 @@
+package pocketsphinx
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strconv"
+)
+
+//StreamOptions configures ProcessStream.
+type StreamOptions struct {
+	//ChunkSize is the number of samples passed to the decoder at a time. It defaults to 100ms of audio.
+	ChunkSize int
+	//OnError is called with errors that end the stream early. Reaching the end of the reader or cancelling the context aren't errors.
+	OnError func(error)
+	//SampleRate is the sampling rate of the audio, zero if it is the decoder's. Unless Resample is set, ProcessStream fails with an error wrapping ErrSampleRate if they differ.
+	SampleRate int
+	//Resample converts audio at another SampleRate to the decoder's.
+	Resample bool
+}
+
+//ProcessStream reads single channel, little-endian 16-bit pcm audio from r and decodes it in the background, splitting utterances where the decoder detects silence. A Result is sent for every utterance that produced a hypothesis. The channel is closed when r is exhausted, ctx is done or an error occurs. The decoder must not be used otherwise until then.
+func (p *PocketSphinx) ProcessStream(ctx context.Context, r io.Reader, opts StreamOptions) (<-chan Result, error) {
+	if r == nil {
+		return nil, errors.New("nil reader")
+	}
+	rate, err := p.decoderRate()
+	if err != nil {
+		return nil, err
+	}
+	var resampler *Resampler
+	if opts.SampleRate > 0 {
+		if err := p.CheckSampleRate(float64(opts.SampleRate)); err != nil {
+			if !opts.Resample || !errors.Is(err, ErrSampleRate) {
+				return nil, err
+			}
+			resampler = NewResampler(opts.SampleRate, int(rate))
+		}
+	}
+	chunkSize := opts.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = int(rate / 10)
+	}
+
+	_, span := p.trace(ctx, "ProcessStream")
+	loop := &uttLoop{p: p}
+	if err := loop.start(); err != nil {
+		span.end(0, 0, err)
+		return nil, err
+	}
+
+	results := make(chan Result)
+	go func() {
+		var read, words int
+		var streamErr error
+		defer func() {
+			span.end(p.samplesDuration(read), words, streamErr)
+		}()
+		defer close(results)
+		send := func(rs []Result) bool {
+			for _, r := range rs {
+				words += len(r.Words)
+				select {
+				case results <- r:
+				case <-ctx.Done():
+					return false
+				}
+			}
+			return true
+		}
+		fail := func(err error) {
+			streamErr = err
+			if opts.OnError != nil {
+				opts.OnError(err)
+			}
+		}
+
+		buf := make([]byte, chunkSize*2)
+		samples := make([]int16, chunkSize)
+		for {
+			select {
+			case <-ctx.Done():
+				p.EndUtt()
+				return
+			default:
+			}
+
+			n, err := io.ReadFull(r, buf)
+			n -= n % 2
+			eof := err == io.EOF || err == io.ErrUnexpectedEOF
+			for i := 0; i < n/2; i++ {
+				samples[i] = int16(binary.LittleEndian.Uint16(buf[i*2:]))
+			}
+			in := samples[:n/2]
+			if resampler != nil {
+				in = resampler.Process(in)
+				if eof {
+					in = append(append([]int16(nil), in...), resampler.Flush()...)
+				}
+			}
+			read += len(in)
+			if len(in) > 0 {
+				rs, perr := loop.process(in)
+				if perr != nil {
+					p.EndUtt()
+					fail(perr)
+					return
+				}
+				if !send(rs) {
+					p.EndUtt()
+					return
+				}
+			}
+			if eof {
+				rs, ferr := loop.finish()
+				if ferr != nil {
+					fail(ferr)
+					return
+				}
+				send(rs)
+				return
+			}
+			if err != nil {
+				p.EndUtt()
+				fail(err)
+				return
+			}
+		}
+	}()
+	return results, nil
+}
+
+//ProcessContinuous processes a chunk of continuous single channel, 16-bit pcm audio, starting and ending utterances where the decoder detects speech and silence. It returns the results of the utterances that ended in raw. Call EndContinuous at the end of the audio.
+func (p *PocketSphinx) ProcessContinuous(raw []int16) ([]Result, error) {
+	if p.cont == nil {
+		cont := &uttLoop{p: p}
+		if err := cont.start(); err != nil {
+			return nil, err
+		}
+		p.cont = cont
+	}
+	//The loop only points back at p while in use, so that p isn't part of a cycle, which would keep its finalizer from running.
+	p.cont.p = p
+	rs, err := p.cont.process(raw)
+	p.cont.p = nil
+	if err != nil {
+		p.cont = nil
+		p.EndUtt()
+	}
+	return rs, err
+}
+
+//EndContinuous ends the utterance started by ProcessContinuous, returning its result if it contained speech.
+func (p *PocketSphinx) EndContinuous() ([]Result, error) {
+	if p.cont == nil {
+		return nil, nil
+	}
+	cont := p.cont
+	p.cont = nil
+	cont.p = p
+	return cont.finish()
+}
+
+//EventType identifies the kind of an Event.
+type EventType int
+
+const (
+	//SpeechStart is sent when speech is detected in an utterance.
+	SpeechStart EventType = iota
+	//SpeechEnd is sent when silence is detected after speech, ending the utterance.
+	SpeechEnd
+	//PartialResult is sent whenever the hypothesis of an utterance in progress changes.
+	PartialResult
+	//FinalResult is sent with the hypothesis of an utterance after it ended.
+	FinalResult
+)
+
+//String returns the name of the event type.
+func (t EventType) String() string {
+	switch t {
+	case SpeechStart:
+		return "SpeechStart"
+	case SpeechEnd:
+		return "SpeechEnd"
+	case PartialResult:
+		return "PartialResult"
+	case FinalResult:
+		return "FinalResult"
+	}
+	return "EventType(" + strconv.Itoa(int(t)) + ")"
+}
+
+//Event is a change in the state of a Stream. Result is set for PartialResult and FinalResult events.
+type Event struct {
+	Type   EventType `json:"type"`
+	Result Result    `json:"result"`
+}
+
+//Stream decodes continuous audio, splitting utterances where the decoder detects silence and reporting progress as Events. Audio is passed with ProcessRaw, or written as single channel, little-endian 16-bit pcm bytes.
+type Stream struct {
+	pcmWriter
+	loop       uttLoop
+	events     chan Event
+	subscribed bool
+	closed     bool
+
+	onSpeechStart   func()
+	onSpeechEnd     func()
+	onPartialResult func(Result)
+	onFinalResult   func(Result)
+}
+
+//NewStream creates Stream decoding with p and starts the first utterance. Once Results has been called, up to buffer events are queued; when the queue is full, processing blocks until Results is read.
+func NewStream(p *PocketSphinx, buffer int) (*Stream, error) {
+	s := &Stream{events: make(chan Event, buffer)}
+	s.loop = uttLoop{p: p, partials: true}
+	s.loop.onEvent = s.emit
+	s.process = s.ProcessRaw
+	if err := s.loop.start(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+//Results gets the channel events are sent on. Events are only sent after the first call, so that a Stream used with callbacks alone doesn't block. It is closed by Close.
+func (s *Stream) Results() <-chan Event {
+	s.subscribed = true
+	return s.events
+}
+
+//OnSpeechStart registers f to be called when speech is detected. Callbacks run on the goroutine processing audio and must be registered before it starts.
+func (s *Stream) OnSpeechStart(f func()) {
+	s.onSpeechStart = f
+}
+
+//OnSpeechEnd registers f to be called when silence is detected after speech.
+func (s *Stream) OnSpeechEnd(f func()) {
+	s.onSpeechEnd = f
+}
+
+//OnPartialResult registers f to be called when the hypothesis of the utterance in progress changes.
+func (s *Stream) OnPartialResult(f func(Result)) {
+	s.onPartialResult = f
+}
+
+//OnFinalResult registers f to be called with the hypothesis of an utterance after it ended.
+func (s *Stream) OnFinalResult(f func(Result)) {
+	s.onFinalResult = f
+}
+
+//ProcessRaw processes a chunk of single channel, 16-bit pcm audio. It returns ErrClosed after Close.
+func (s *Stream) ProcessRaw(raw []int16) error {
+	if s.closed {
+		return ErrClosed
+	}
+	_, err := s.loop.process(raw)
+	return err
+}
+
+//Flush ends the current utterance, sending its final result, and starts a new one.
+func (s *Stream) Flush() error {
+	if s.closed {
+		return ErrClosed
+	}
+	if _, err := s.loop.finish(); err != nil {
+		return err
+	}
+	return s.loop.start()
+}
+
+//Close ends the current utterance, sending its final result, and closes the Results channel. Closing a closed Stream does nothing.
+func (s *Stream) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	_, err := s.loop.finish()
+	close(s.events)
+	return err
+}
+
+func (s *Stream) emit(ev Event) {
+	switch {
+	case ev.Type == SpeechStart && s.onSpeechStart != nil:
+		s.onSpeechStart()
+	case ev.Type == SpeechEnd && s.onSpeechEnd != nil:
+		s.onSpeechEnd()
+	case ev.Type == PartialResult && s.onPartialResult != nil:
+		s.onPartialResult(ev.Result)
+	case ev.Type == FinalResult && s.onFinalResult != nil:
+		s.onFinalResult(ev.Result)
+	}
+	if s.subscribed {
+		s.events <- ev
+	}
+}
+
+//uttLoop splits continuous audio into utterances using the decoder's voice activity detection, like the main loop of pocketsphinx_continuous.
+type uttLoop struct {
+	p *PocketSphinx
+	//inSpeech is set once speech was detected in the current utterance.
+	inSpeech bool
+
+	//onEvent, if set, is called on every state change.
+	onEvent func(Event)
+	//partials enables PartialResult events.
+	partials bool
+	//partial is the text of the last PartialResult.
+	partial string
+}
+
+func (l *uttLoop) start() error {
+	l.inSpeech = false
+	l.partial = ""
+	return l.p.StartUtt()
+}
+
+func (l *uttLoop) emit(ev Event) {
+	if l.onEvent != nil {
+		l.onEvent(ev)
+	}
+}
+
+//process decodes raw and returns the results of utterances that ended in it.
+func (l *uttLoop) process(raw []int16) ([]Result, error) {
+	if _, err := l.p.processNative(raw, false, false); err != nil {
+		return nil, err
+	}
+	inSpeech := l.p.IsInSpeech()
+	if inSpeech && !l.inSpeech {
+		l.inSpeech = true
+		l.emit(Event{Type: SpeechStart})
+	}
+	if !inSpeech && l.inSpeech {
+		l.emit(Event{Type: SpeechEnd})
+		rs, err := l.end()
+		if err != nil {
+			return rs, err
+		}
+		return rs, l.start()
+	}
+	if l.inSpeech && l.partials {
+		if hyp, err := l.p.GetHyp(); err == nil && hyp.Text != l.partial {
+			l.partial = hyp.Text
+			l.emit(Event{Type: PartialResult, Result: hyp})
+		}
+	}
+	return nil, nil
+}
+
+//finish ends the current utterance, returning its result if speech was detected in it.
+func (l *uttLoop) finish() ([]Result, error) {
+	if !l.inSpeech {
+		return nil, l.p.EndUtt()
+	}
+	l.emit(Event{Type: SpeechEnd})
+	return l.end()
+}
+
+func (l *uttLoop) end() ([]Result, error) {
+	l.inSpeech = false
+	if err := l.p.EndUtt(); err != nil {
+		return nil, err
+	}
+	hyp, err := l.p.GetHyp()
+	if errors.Is(err, ErrNoHypothesis) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	l.emit(Event{Type: FinalResult, Result: hyp})
+	return []Result{hyp}, nil
+}
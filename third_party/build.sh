@@ -0,0 +1,59 @@
+#!/bin/sh
+# Builds static sphinxbase and pocketsphinx 5prealpha libraries into third_party/install, for the pocketsphinx_vendor
+# build tag. Run through go generate from the package directory. Cross-compile by setting HOST to the target triplet
+# and CC to its compiler, e.g. HOST=x86_64-w64-mingw32 CC=x86_64-w64-mingw32-gcc.
+#
+# The sources aren't bundled with the repository. The release tarballs are downloaded from SourceForge, which needs
+# network access, unless they were copied into third_party/src beforehand, e.g. for a build host without it. Either way
+# they are checked against the sha256 pinned in third_party/SHA256SUMS before being extracted, and the build stops if a
+# tarball has no pinned checksum or doesn't match it. Once third_party/install is built, go build -tags
+# pocketsphinx_vendor needs neither the network nor the C libraries installed.
+set -e
+VERSION=5prealpha
+SF=https://sourceforge.net/projects/cmusphinx/files
+DIR=$(cd "$(dirname "$0")" && pwd)
+PREFIX="$DIR/install"
+SUMS="$DIR/SHA256SUMS"
+mkdir -p "$DIR/src"
+cd "$DIR/src"
+
+sha256() {
+	if command -v sha256sum >/dev/null 2>&1; then
+		sha256sum "$1"
+	else
+		shasum -a 256 "$1"
+	fi | cut -d ' ' -f 1
+}
+
+verify() {
+	want=$(awk -v f="$1" '$1 !~ /^#/ && $2 == f { print $1 }' "$SUMS")
+	if [ -z "$want" ]; then
+		echo "build.sh: no sha256 pinned for $1 in $SUMS, add the one published with the release" >&2
+		exit 1
+	fi
+	got=$(sha256 "$1")
+	if [ "$got" != "$want" ]; then
+		echo "build.sh: $1 has sha256 $got, want $want" >&2
+		rm -f "$1"
+		exit 1
+	fi
+}
+
+for pkg in sphinxbase pocketsphinx; do
+	tarball="$pkg-$VERSION.tar.gz"
+	if [ ! -d "$pkg-$VERSION" ]; then
+		if [ ! -f "$tarball" ]; then
+			echo "build.sh: downloading $tarball, put it in $DIR/src to build without network access" >&2
+			curl -fL -o "$tarball" "$SF/$pkg/$VERSION/$tarball/download"
+		fi
+		verify "$tarball"
+		tar -xzf "$tarball"
+	fi
+	(
+		cd "$pkg-$VERSION"
+		PKG_CONFIG_PATH="$PREFIX/lib/pkgconfig" ./configure --prefix="$PREFIX" ${HOST:+--host="$HOST"} \
+			--disable-shared --enable-static --with-pic --without-python
+		make
+		make install
+	)
+done
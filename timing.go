@@ -0,0 +1,79 @@
+package pocketsphinx
+
+/*
+#include <pocketsphinx.h>
+*/
+import "C"
+
+import (
+	"time"
+)
+
+//NumFrames gets the number of frames of audio decoded in the current or last utterance. Frames dropped as silence by voice activity detection aren't counted.
+func (p *PocketSphinx) NumFrames() int {
+	if p.acquire() != nil {
+		return 0
+	}
+	defer p.release()
+	return int(C.ps_get_n_frames(p.ps))
+}
+
+//UttDuration gets the duration of the audio decoded in the current or last utterance, as counted by NumFrames.
+func (p *PocketSphinx) UttDuration() time.Duration {
+	if p.ps == nil || p.frate == 0 {
+		return 0
+	}
+	return time.Duration(float64(p.NumFrames()) / p.frate * float64(time.Second))
+}
+
+//Timing is the time spent decoding, as speech decoded, CPU time and wall time.
+type Timing struct {
+	Speech time.Duration `json:"speech"`
+	CPU    time.Duration `json:"cpu"`
+	Wall   time.Duration `json:"wall"`
+}
+
+//RTF gets the real-time factor, the wall time spent per second of speech. Values below 1 are faster than real time.
+func (t Timing) RTF() float64 {
+	if t.Speech == 0 {
+		return 0
+	}
+	return t.Wall.Seconds() / t.Speech.Seconds()
+}
+
+//CPURTF gets the CPU time spent per second of speech.
+func (t Timing) CPURTF() float64 {
+	if t.Speech == 0 {
+		return 0
+	}
+	return t.CPU.Seconds() / t.Speech.Seconds()
+}
+
+//Stats is the performance of a decoder in the last utterance and since it was created.
+type Stats struct {
+	Utt   Timing `json:"utt"`
+	Total Timing `json:"total"`
+}
+
+//Stats gets the performance of the decoder.
+func (p *PocketSphinx) Stats() (Stats, error) {
+	if err := p.acquire(); err != nil {
+		return Stats{}, err
+	}
+	defer p.release()
+	var s Stats
+	var speech, cpu, wall C.double
+	C.ps_get_utt_time(p.ps, &speech, &cpu, &wall)
+	s.Utt = timing(speech, cpu, wall)
+	C.ps_get_all_time(p.ps, &speech, &cpu, &wall)
+	s.Total = timing(speech, cpu, wall)
+	return s, nil
+}
+
+func timing(speech, cpu, wall C.double) Timing {
+	return Timing{
+		Speech: seconds(float64(speech)),
+		CPU:    seconds(float64(cpu)),
+		Wall:   seconds(float64(wall)),
+	}
+}
@@ -0,0 +1,83 @@
+package pocketsphinx
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+//Tracer starts spans for decode operations, e.g. to trace transcription latency with OpenTelemetry, see the oteltrace package. Spans of operations running others, such as TranscribeFile running ProcessUttContext, are parents of theirs through the returned context.
+type Tracer interface {
+	Start(ctx context.Context, op string) (context.Context, Span)
+}
+
+//Span is a traced decode operation.
+type Span interface {
+	//End ends the span, with the attributes of the operation and its error, if any.
+	End(attrs SpanAttributes, err error)
+}
+
+//SpanAttributes describe a decode operation. RTF is the wall time spent per second of audio.
+type SpanAttributes struct {
+	Audio  time.Duration
+	Search string
+	Words  int
+	RTF    float64
+}
+
+type tracerHolder struct {
+	t Tracer
+}
+
+var tracer atomic.Value
+
+//SetTracer registers t to trace ProcessUtt, ProcessUttContext, ProcessStream and TranscribeFile, replacing the previous one. A nil t disables tracing.
+func SetTracer(t Tracer) {
+	tracer.Store(tracerHolder{t: t})
+}
+
+//opSpan is a span in progress, nil when no Tracer is registered.
+type opSpan struct {
+	span   Span
+	start  time.Time
+	search string
+}
+
+//trace starts a span for op if a Tracer is registered.
+func (p *PocketSphinx) trace(ctx context.Context, op string) (context.Context, *opSpan) {
+	h, _ := tracer.Load().(tracerHolder)
+	if h.t == nil {
+		return ctx, nil
+	}
+	ctx, span := h.t.Start(ctx, op)
+	return ctx, &opSpan{span: span, start: time.Now(), search: p.GetSearch()}
+}
+
+//end ends the span of an operation that decoded audio and recognized words.
+func (s *opSpan) end(audio time.Duration, words int, err error) {
+	if s == nil {
+		return
+	}
+	attrs := SpanAttributes{Audio: audio, Search: s.search, Words: words}
+	if audio > 0 {
+		attrs.RTF = time.Since(s.start).Seconds() / audio.Seconds()
+	}
+	s.span.End(attrs, err)
+}
+
+//samplesDuration gets the duration of n samples at the decoder's sampling rate.
+func (p *PocketSphinx) samplesDuration(n int) time.Duration {
+	rate := p.sampleRate()
+	if rate == 0 {
+		return 0
+	}
+	return time.Duration(float64(n) / rate * float64(time.Second))
+}
+
+//countWords gets the number of words of the best of rs.
+func countWords(rs []Result) int {
+	if len(rs) == 0 {
+		return 0
+	}
+	return len(rs[0].Words)
+}
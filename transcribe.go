@@ -0,0 +1,139 @@
+package pocketsphinx
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+//Transcript is the transcription of a recording, utterance by utterance.
+type Transcript struct {
+	Utterances []Utterance   `json:"utterances"`
+	Duration   time.Duration `json:"duration"`
+}
+
+//Utterance is a transcribed segment of a recording. Its start and end, and the times of its words, are offsets from the start of the recording.
+type Utterance struct {
+	Start  time.Duration `json:"start"`
+	End    time.Duration `json:"end"`
+	Result Result        `json:"result"`
+}
+
+//Text gets the text of all utterances, separated by spaces.
+func (t Transcript) Text() string {
+	texts := make([]string, 0, len(t.Utterances))
+	for _, u := range t.Utterances {
+		if u.Result.Text != "" {
+			texts = append(texts, u.Result.Text)
+		}
+	}
+	return strings.Join(texts, " ")
+}
+
+//transcribeChunk is the duration of audio read from a file at a time.
+const transcribeChunk = time.Second
+
+//TranscribeFile transcribes the WAV file at path, which must be 16-bit, single channel pcm. Audio at another sampling rate than the decoder's is resampled. The audio is read in chunks and split into utterances at silences, so files of any length can be transcribed. If progress isn't nil it is called after every chunk with the duration of audio read so far and in total.
+func (p *PocketSphinx) TranscribeFile(path string, progress func(done, total time.Duration)) (Transcript, error) {
+	return p.TranscribeFileContext(context.Background(), path, progress)
+}
+
+//TranscribeFileContext is like TranscribeFile, but stops when ctx is done, returning the utterances transcribed so far and ctx.Err().
+func (p *PocketSphinx) TranscribeFileContext(ctx context.Context, path string, progress func(done, total time.Duration)) (Transcript, error) {
+	ctx, span := p.trace(ctx, "TranscribeFile")
+	t, err := p.transcribeFile(ctx, path, progress)
+	words := 0
+	for _, u := range t.Utterances {
+		words += len(u.Result.Words)
+	}
+	span.end(t.Duration, words, err)
+	return t, err
+}
+
+func (p *PocketSphinx) transcribeFile(ctx context.Context, path string, progress func(done, total time.Duration)) (Transcript, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Transcript{}, err
+	}
+	defer f.Close()
+
+	w, err := NewWAVReader(f)
+	if err != nil {
+		return Transcript{}, err
+	}
+	sampleRate, err := p.decoderRate()
+	if err != nil {
+		return Transcript{}, err
+	}
+	format := w.Format
+	var resampler *Resampler
+	if format.SampleRate > 0 && float64(format.SampleRate) != sampleRate {
+		resampler = NewResampler(format.SampleRate, int(sampleRate))
+		format.SampleRate = int(sampleRate)
+	}
+	if err := format.Validate(sampleRate); err != nil {
+		return Transcript{}, err
+	}
+
+	t := Transcript{Duration: w.Duration()}
+	seg := NewSegmenter(SegmenterOptions{SampleRate: int(sampleRate)})
+	buf := make([]byte, int(sampleRate*transcribeChunk.Seconds())*2)
+	var raw, samples []int16
+	var done int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return t, err
+		}
+		n, rerr := io.ReadFull(w, buf)
+		raw = appendSamples(raw[:0], buf[:n])
+		samples = raw
+		if resampler != nil {
+			samples = resampler.Process(raw)
+			if rerr != nil {
+				samples = append(append([]int16(nil), samples...), resampler.Flush()...)
+			}
+		}
+		done += int64(len(samples))
+		for _, s := range seg.Process(samples) {
+			if err := p.transcribeSegment(ctx, &t, s); err != nil {
+				return t, err
+			}
+		}
+		if progress != nil {
+			progress(time.Duration(float64(done)/sampleRate*float64(time.Second)), t.Duration)
+		}
+		if rerr == io.EOF || rerr == io.ErrUnexpectedEOF {
+			break
+		}
+		if rerr != nil {
+			return t, rerr
+		}
+	}
+	for _, s := range seg.Flush() {
+		if err := p.transcribeSegment(ctx, &t, s); err != nil {
+			return t, err
+		}
+	}
+	return t, nil
+}
+
+//transcribeSegment decodes s and appends it to t, unless it had no hypothesis.
+func (p *PocketSphinx) transcribeSegment(ctx context.Context, t *Transcript, s SpeechSegment) error {
+	rs, err := p.ProcessUttContext(ctx, s.Samples, 1)
+	if err == ErrNoHypothesis {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	r := rs[0]
+	offset := s.Start.Seconds()
+	for i := range r.Words {
+		r.Words[i].Start += offset
+		r.Words[i].End += offset
+	}
+	t.Utterances = append(t.Utterances, Utterance{Start: s.Start, End: s.End, Result: r})
+	return nil
+}
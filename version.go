@@ -0,0 +1,137 @@
+package pocketsphinx
+
+/*
+#include <pocketsphinx.h>
+#include <stdlib.h>
+cmd_ln_t *default_config();
+
+// Functions declared weak are NULL when missing from the linked library, so optional features are detected from the
+// library itself. Where weak symbols aren't supported, they are assumed from the API selected when building.
+#if defined(__GNUC__) && !defined(_WIN32) && !defined(__APPLE__)
+#define HAVE_WEAK 1
+#define HAVE_SYMBOL(f) ((f) != NULL)
+#else
+#define HAVE_SYMBOL(f) 1
+#endif
+
+#ifdef HAVE_WEAK
+const char *ps_get_version(void) __attribute__((weak));
+#endif
+
+static const char *library_version(){
+#ifdef HAVE_WEAK
+    if (ps_get_version != NULL)
+        return ps_get_version();
+#endif
+#ifdef PACKAGE_VERSION
+    return PACKAGE_VERSION;
+#else
+    return NULL;
+#endif
+}
+
+#ifdef POCKETSPHINX5
+#include <pocketsphinx/endpointer.h>
+#include <pocketsphinx/alignment.h>
+#ifdef HAVE_WEAK
+#pragma weak ps_endpointer_init
+#pragma weak ps_set_align_text
+#pragma weak ps_get_alignment
+#endif
+static int have_endpointer(){ return HAVE_SYMBOL(ps_endpointer_init); }
+static int have_alignment(){ return HAVE_SYMBOL(ps_set_align_text) && HAVE_SYMBOL(ps_get_alignment); }
+#else
+#ifdef HAVE_WEAK
+#pragma weak ps_set_fsg
+#pragma weak fsg_model_init
+#endif
+static int have_endpointer(){ return 0; }
+static int have_alignment(){ return HAVE_SYMBOL(ps_set_fsg) && HAVE_SYMBOL(fsg_model_init); }
+#endif
+*/
+import "C"
+
+import (
+	"sync"
+	"unsafe"
+)
+
+//VersionInfo is the version of the pocketsphinx and sphinxbase libraries the package is built against.
+type VersionInfo struct {
+	PocketSphinx string `json:"pocketsphinx"`
+	//SphinxBase is empty when it is part of pocketsphinx, as with pocketsphinx 5.
+	SphinxBase string `json:"sphinxbase,omitempty"`
+}
+
+//String formats v like "pocketsphinx 5prealpha, sphinxbase 5prealpha".
+func (v VersionInfo) String() string {
+	if v.SphinxBase == "" {
+		return "pocketsphinx " + v.PocketSphinx
+	}
+	return "pocketsphinx " + v.PocketSphinx + ", sphinxbase " + v.SphinxBase
+}
+
+//Version gets the version of the linked libraries. That of pocketsphinx is reported by ps_get_version where the library has it, or by PACKAGE_VERSION if defined when building, e.g. from pkg-config:
+//
+//	CGO_CFLAGS="-DPACKAGE_VERSION=\"$(pkg-config --modversion pocketsphinx)\"" go build
+//
+//Otherwise, and for sphinxbase, it is the version of the API selected by the pocketsphinx5 build tag.
+func Version() VersionInfo {
+	v := VersionInfo{PocketSphinx: pocketsphinxVersion, SphinxBase: sphinxbaseVersion}
+	if cv := C.library_version(); cv != nil {
+		v.PocketSphinx = C.GoString(cv)
+	}
+	return v
+}
+
+//CapabilityInfo reports which optional features are available with the linked libraries, so applications can degrade gracefully rather than failing with ErrUnsupported.
+type CapabilityInfo struct {
+	//Endpointer is set when Endpointer uses the endpointer of the library, ps_endpointer_t of pocketsphinx 5, rather than its fallback in Go, which is always available.
+	Endpointer bool `json:"endpointer"`
+	//Alignment is set when Align and AlignPhones are available, as they need the FSG search of the legacy API, or the alignment search of pocketsphinx 5.
+	Alignment bool `json:"alignment"`
+	//Kws is set when keyword spotting searches are available, with SetKeyphrase and SetKeywordList.
+	Kws bool `json:"kws"`
+	//KwsFile is set when keyword lists can be read from files, with SetKwsFile and WithKws.
+	KwsFile bool `json:"kws_file"`
+	//Allphone is set when phone recognition searches are available.
+	Allphone bool `json:"allphone"`
+	//FeatureExtraction is set when FeatureExtractor is available.
+	FeatureExtraction bool `json:"feature_extraction"`
+	//AGC is set when AGCEstimate is available.
+	AGC bool `json:"agc"`
+	//ArgFile is set when NewFromArgs honors -argfile.
+	ArgFile bool `json:"argfile"`
+}
+
+var (
+	capsOnce sync.Once
+	caps     CapabilityInfo
+)
+
+//Capabilities gets the optional features available with the linked libraries, detected the first time it is called. The endpointer and alignment are detected from the functions of the library, where the platform supports weak symbols, the searches from the options it accepts.
+func Capabilities() CapabilityInfo {
+	capsOnce.Do(func() {
+		psConfig := C.default_config()
+		if psConfig == nil {
+			return
+		}
+		defer C.cmd_ln_free_r(psConfig)
+		hasOption := func(name string) bool {
+			cname := C.CString(name)
+			defer C.free(unsafe.Pointer(cname))
+			return C.cmd_ln_exists_r(psConfig, cname) != 0
+		}
+		caps = CapabilityInfo{
+			Endpointer:        C.have_endpointer() != 0,
+			Alignment:         C.have_alignment() != 0,
+			Kws:               hasOption("-keyphrase"),
+			KwsFile:           hasOption("-kws"),
+			Allphone:          hasOption("-allphone"),
+			FeatureExtraction: legacyAPI,
+			AGC:               legacyAPI,
+			ArgFile:           legacyAPI,
+		}
+	})
+	return caps
+}
@@ -0,0 +1,57 @@
+package pocketsphinx
+
+//VisemeMap maps the phones of the acoustic model to visemes, the mouth shapes of an animated character. Rest is the viseme used between words and for phones missing from Phones.
+type VisemeMap struct {
+	Phones map[string]string
+	Rest   string
+}
+
+//Viseme is a mouth shape held from Start to End, in seconds.
+type Viseme struct {
+	Viseme string  `json:"viseme"`
+	Start  float64 `json:"start"`
+	End    float64 `json:"end"`
+}
+
+//OculusVisemes maps the CMU phones of the en-us model to the 15 visemes of the Oculus lip sync set, which are also used by many other animation tools.
+var OculusVisemes = VisemeMap{
+	Rest: "sil",
+	Phones: map[string]string{
+		"AA": "aa", "AE": "aa", "AH": "aa", "AO": "oh", "AW": "aa", "AY": "aa",
+		"B": "PP", "CH": "CH", "D": "DD", "DH": "TH", "EH": "E", "ER": "RR",
+		"EY": "E", "F": "FF", "G": "kk", "HH": "kk", "IH": "ih", "IY": "ih",
+		"JH": "CH", "K": "kk", "L": "nn", "M": "PP", "N": "nn", "NG": "nn",
+		"OW": "oh", "OY": "oh", "P": "PP", "R": "RR", "S": "SS", "SH": "CH",
+		"T": "DD", "TH": "TH", "UH": "ou", "UW": "ou", "V": "FF", "W": "ou",
+		"Y": "ih", "Z": "SS", "ZH": "CH",
+	},
+}
+
+//Visemes converts the phones of an alignment made by AlignPhones to visemes with m, filling the gaps between words with the rest viseme and merging consecutive identical visemes. The result covers the audio from 0 to the end of the last word.
+func (m VisemeMap) Visemes(words []WordAlignment) []Viseme {
+	var ret []Viseme
+	add := func(viseme string, start, end float64) {
+		if end <= start {
+			return
+		}
+		if n := len(ret); n > 0 && ret[n-1].Viseme == viseme {
+			ret[n-1].End = end
+			return
+		}
+		ret = append(ret, Viseme{Viseme: viseme, Start: start, End: end})
+	}
+
+	var t float64
+	for _, word := range words {
+		for _, phone := range word.Phones {
+			add(m.Rest, t, phone.Start)
+			viseme, ok := m.Phones[phone.Phone]
+			if !ok {
+				viseme = m.Rest
+			}
+			add(viseme, phone.Start, phone.End)
+			t = phone.End
+		}
+	}
+	return ret
+}
@@ -0,0 +1,175 @@
+package pocketsphinx
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+//WakeWordOptions configures NewWakeWord.
+type WakeWordOptions struct {
+	//Keywords are the phrases to spot.
+	Keywords []Keyword
+	//Threshold is used for keywords without a threshold of their own. If zero, -kws_threshold is used.
+	Threshold float64
+	//Debounce is the time after a detection during which further detections of the same keyword are ignored. It defaults to one second.
+	Debounce time.Duration
+	//Search is the name of the keyword search added to the decoder, "wakeword" if empty.
+	Search string
+}
+
+//WakeDetection is a keyword detected by a WakeWord, with its start and end time since the WakeWord was created, and its detection score in the log domain.
+type WakeDetection struct {
+	Keyword string        `json:"keyword"`
+	Start   time.Duration `json:"start"`
+	End     time.Duration `json:"end"`
+	Score   int64         `json:"score"`
+}
+
+//WakeWord spots keywords in continuous audio with a keyword search, calling the callback registered with OnDetect for each detection. Audio is passed with ProcessRaw, or written as single channel, little-endian 16-bit pcm bytes.
+type WakeWord struct {
+	pcmWriter
+	p        *PocketSphinx
+	debounce time.Duration
+	onDetect func(WakeDetection)
+
+	//samples is the number of samples processed, uttStart the number processed before the current utterance.
+	samples  int64
+	uttStart int64
+	inSpeech bool
+	last     map[string]time.Duration
+}
+
+//NewWakeWord adds a keyword search for opts.Keywords to p, activates it and starts the first utterance. p must not be used otherwise until the WakeWord is closed.
+func NewWakeWord(p *PocketSphinx, opts WakeWordOptions) (*WakeWord, error) {
+	if len(opts.Keywords) == 0 {
+		return nil, errors.New("wakeword error:no keywords")
+	}
+	if opts.Debounce == 0 {
+		opts.Debounce = time.Second
+	}
+	if opts.Search == "" {
+		opts.Search = "wakeword"
+	}
+	kws := make([]Keyword, len(opts.Keywords))
+	for i, kw := range opts.Keywords {
+		if kw.Threshold == 0 {
+			kw.Threshold = opts.Threshold
+		}
+		kws[i] = kw
+	}
+	if err := p.SetKeywordList(opts.Search, kws); err != nil {
+		return nil, err
+	}
+	if err := p.SetSearch(opts.Search); err != nil {
+		return nil, err
+	}
+	if err := p.StartUtt(); err != nil {
+		return nil, err
+	}
+
+	w := &WakeWord{p: p, debounce: opts.Debounce, last: make(map[string]time.Duration)}
+	w.process = w.ProcessRaw
+	return w, nil
+}
+
+//OnDetect registers f to be called with every detection. It runs on the goroutine processing audio and must be registered before it starts.
+func (w *WakeWord) OnDetect(f func(WakeDetection)) {
+	w.onDetect = f
+}
+
+//ProcessRaw processes a chunk of single channel, 16-bit pcm audio.
+func (w *WakeWord) ProcessRaw(raw []int16) error {
+	if _, err := w.p.processNative(raw, false, false); err != nil {
+		return err
+	}
+	w.samples += int64(len(raw))
+
+	dets, err := w.p.GetKeywordDetections()
+	if err != nil {
+		return err
+	}
+	inSpeech := w.p.IsInSpeech()
+	speechEnded := w.inSpeech && !inSpeech
+	w.inSpeech = inSpeech
+	if len(dets) == 0 && !speechEnded {
+		return nil
+	}
+
+	//Restart the utterance so detections aren't reported again, and so it doesn't grow without bound.
+	offset := w.duration(w.uttStart)
+	if err := w.p.EndUtt(); err != nil {
+		return err
+	}
+	w.uttStart = w.samples
+	w.inSpeech = false
+	for _, det := range dets {
+		wd := WakeDetection{
+			Keyword: det.Keyword,
+			Start:   offset + seconds(det.Start),
+			End:     offset + seconds(det.End),
+			Score:   det.Score,
+		}
+		if last, ok := w.last[wd.Keyword]; ok && wd.Start-last < w.debounce {
+			continue
+		}
+		w.last[wd.Keyword] = wd.Start
+		if w.onDetect != nil {
+			w.onDetect(wd)
+		}
+	}
+	return w.p.StartUtt()
+}
+
+//Run reads audio from src until it is exhausted or ctx is done. It returns ErrClosed once the decoder is freed.
+func (w *WakeWord) Run(ctx context.Context, src io.Reader) error {
+	rate, err := w.p.decoderRate()
+	if err != nil {
+		return err
+	}
+	buf := make([]byte, int(rate/10)*2)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		n, err := src.Read(buf)
+		if n > 0 {
+			if _, werr := w.Write(buf[:n]); werr != nil {
+				return werr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+//resume restarts spotting after the decoder was used for something else until samples samples of audio had been passed.
+func (w *WakeWord) resume(search string, samples int64) error {
+	if err := w.p.SetSearch(search); err != nil {
+		return err
+	}
+	w.samples = samples
+	w.uttStart = samples
+	w.inSpeech = false
+	return w.p.StartUtt()
+}
+
+//Close ends the current utterance. The keyword search stays active on the decoder.
+func (w *WakeWord) Close() error {
+	return w.p.EndUtt()
+}
+
+func (w *WakeWord) duration(samples int64) time.Duration {
+	return time.Duration(float64(samples) / w.p.sampleRate() * float64(time.Second))
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,115 @@
+package pocketsphinx
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+//GrammarWatcher keeps a search up to date with a JSGF grammar or keyword list file, so voice commands can be edited while a service runs. Files ending in .kws or .list are read as keyword lists, others as JSGF grammars.
+//
+//The file is watched in the background, but since a decoder can't be used concurrently, the search is only replaced by Reload, which should be called between utterances on the goroutine decoding audio, e.g. after receiving from Changed.
+type GrammarWatcher struct {
+	p       *PocketSphinx
+	name    string
+	path    string
+	watcher *fsnotify.Watcher
+	changed chan struct{}
+	pending int32
+
+	mu  sync.Mutex
+	err error
+}
+
+//NewGrammarWatcher adds a search named name for the file at path and starts watching it.
+func NewGrammarWatcher(p *PocketSphinx, name, path string) (*GrammarWatcher, error) {
+	w := &GrammarWatcher{p: p, name: name, path: filepath.Clean(path), changed: make(chan struct{}, 1)}
+	if err := w.load(); err != nil {
+		return nil, err
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	//Watch the directory rather than the file, as editors often replace files instead of writing to them.
+	if err := watcher.Add(filepath.Dir(w.path)); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+	w.watcher = watcher
+	go w.watch()
+	return w, nil
+}
+
+//Changed gets a channel receiving a value when the file changed since the last Reload.
+func (w *GrammarWatcher) Changed() <-chan struct{} {
+	return w.changed
+}
+
+//Reload replaces the search with the current content of the file if it changed, and reports whether it did. If the file is invalid, the previous search is kept and the error returned, as are errors watching the file. The search stays active if it was. No utterance may be in progress.
+func (w *GrammarWatcher) Reload() (bool, error) {
+	w.mu.Lock()
+	err := w.err
+	w.err = nil
+	w.mu.Unlock()
+	if err != nil {
+		return false, err
+	}
+	if !atomic.CompareAndSwapInt32(&w.pending, 1, 0) {
+		return false, nil
+	}
+	active := w.p.GetSearch() == w.name
+	if err := w.load(); err != nil {
+		return false, err
+	}
+	if active {
+		if err := w.p.SetSearch(w.name); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+//Close stops watching the file. The search stays on the decoder.
+func (w *GrammarWatcher) Close() error {
+	return w.watcher.Close()
+}
+
+//load adds the search for the file, replacing the previous one only if the file is valid.
+func (w *GrammarWatcher) load() error {
+	switch strings.ToLower(filepath.Ext(w.path)) {
+	case ".kws", ".list":
+		return w.p.SetKwsFile(w.name, w.path)
+	default:
+		return w.p.SetJSGFFile(w.name, w.path)
+	}
+}
+
+func (w *GrammarWatcher) watch() {
+	for {
+		select {
+		case ev, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(ev.Name) != w.path || ev.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+			atomic.StoreInt32(&w.pending, 1)
+			select {
+			case w.changed <- struct{}{}:
+			default:
+			}
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			w.mu.Lock()
+			w.err = err
+			w.mu.Unlock()
+		}
+	}
+}
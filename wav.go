@@ -0,0 +1,192 @@
+package pocketsphinx
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+//WAV audio format codes.
+const (
+	wavFormatPCM        = 1
+	wavFormatExtensible = 0xFFFE
+)
+
+//WAVFormat describes the audio in a WAV file.
+type WAVFormat struct {
+	AudioFormat   uint16
+	Channels      int
+	SampleRate    int
+	BitsPerSample int
+}
+
+//WAVReader reads the pcm audio of a WAV file. Read returns the raw bytes of the data chunk.
+type WAVReader struct {
+	Format WAVFormat
+	//DataSize is the size in bytes of the audio data.
+	DataSize int64
+
+	data *io.LimitedReader
+}
+
+//NewWAVReader parses the headers of a WAV file from r, leaving it positioned at the start of the audio data.
+func NewWAVReader(r io.Reader) (*WAVReader, error) {
+	var riff [12]byte
+	if _, err := io.ReadFull(r, riff[:]); err != nil {
+		return nil, fmt.Errorf("wav error:%v", err)
+	}
+	if string(riff[0:4]) != "RIFF" || string(riff[8:12]) != "WAVE" {
+		return nil, errors.New("wav error:not a RIFF WAVE file")
+	}
+
+	w := &WAVReader{}
+	haveFormat := false
+	for {
+		var hdr [8]byte
+		if _, err := io.ReadFull(r, hdr[:]); err != nil {
+			return nil, fmt.Errorf("wav error:no data chunk:%v", err)
+		}
+		id := string(hdr[0:4])
+		size := int64(binary.LittleEndian.Uint32(hdr[4:8]))
+		switch id {
+		case "fmt ":
+			if size < 16 {
+				return nil, errors.New("wav error:short fmt chunk")
+			}
+			buf := make([]byte, size+size%2)
+			if _, err := io.ReadFull(r, buf); err != nil {
+				return nil, fmt.Errorf("wav error:%v", err)
+			}
+			w.Format = WAVFormat{
+				AudioFormat:   binary.LittleEndian.Uint16(buf[0:2]),
+				Channels:      int(binary.LittleEndian.Uint16(buf[2:4])),
+				SampleRate:    int(binary.LittleEndian.Uint32(buf[4:8])),
+				BitsPerSample: int(binary.LittleEndian.Uint16(buf[14:16])),
+			}
+			if w.Format.AudioFormat == wavFormatExtensible && size >= 26 {
+				w.Format.AudioFormat = binary.LittleEndian.Uint16(buf[24:26])
+			}
+			haveFormat = true
+		case "data":
+			if !haveFormat {
+				return nil, errors.New("wav error:data chunk before fmt chunk")
+			}
+			w.DataSize = size
+			w.data = &io.LimitedReader{R: r, N: size}
+			return w, nil
+		default:
+			if _, err := io.CopyN(io.Discard, r, size+size%2); err != nil {
+				return nil, fmt.Errorf("wav error:%v", err)
+			}
+		}
+	}
+}
+
+//Read implements io.Reader, returning the audio data.
+func (w *WAVReader) Read(buf []byte) (int, error) {
+	return w.data.Read(buf)
+}
+
+//Duration gets the length of the audio.
+func (w *WAVReader) Duration() time.Duration {
+	bytesPerSec := int64(w.Format.SampleRate * w.Format.Channels * w.Format.BitsPerSample / 8)
+	if bytesPerSec == 0 {
+		return 0
+	}
+	return time.Duration(w.DataSize) * time.Second / time.Duration(bytesPerSec)
+}
+
+//Validate checks that the audio is 16-bit, single channel pcm at sampleRate, which is what the decoder accepts.
+func (f WAVFormat) Validate(sampleRate float64) error {
+	if f.AudioFormat != wavFormatPCM {
+		return fmt.Errorf("wav error:unsupported audio format %d, want pcm", f.AudioFormat)
+	}
+	if f.BitsPerSample != 16 {
+		return fmt.Errorf("wav error:unsupported %d bits per sample, want 16", f.BitsPerSample)
+	}
+	if f.Channels != 1 {
+		return fmt.Errorf("wav error:%d channels, want 1", f.Channels)
+	}
+	if float64(f.SampleRate) != sampleRate {
+		return fmt.Errorf("wav error:%w:audio at %dHz, decoder -samprate is %gHz", ErrSampleRate, f.SampleRate, sampleRate)
+	}
+	return nil
+}
+
+//ReadWAV reads a whole WAV file of 16-bit pcm audio from r. Multi-channel audio is returned interleaved.
+func ReadWAV(r io.Reader) (WAVFormat, []int16, error) {
+	w, err := NewWAVReader(r)
+	if err != nil {
+		return WAVFormat{}, nil, err
+	}
+	if w.Format.AudioFormat != wavFormatPCM || w.Format.BitsPerSample != 16 {
+		return w.Format, nil, fmt.Errorf("wav error:unsupported audio format %d with %d bits per sample, want 16-bit pcm", w.Format.AudioFormat, w.Format.BitsPerSample)
+	}
+	data, err := io.ReadAll(w)
+	if err != nil {
+		return w.Format, nil, fmt.Errorf("wav error:%v", err)
+	}
+	samples := make([]int16, len(data)/2)
+	for i := range samples {
+		samples[i] = int16(binary.LittleEndian.Uint16(data[i*2:]))
+	}
+	return w.Format, samples, nil
+}
+
+//WriteWAV writes samples, single channel 16-bit pcm audio at sampleRate, to w as a WAV file.
+func WriteWAV(w io.Writer, sampleRate int, samples []int16) error {
+	dataSize := len(samples) * 2
+	buf := make([]byte, 44+dataSize)
+	copy(buf[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(buf[4:8], uint32(36+dataSize))
+	copy(buf[8:16], "WAVEfmt ")
+	binary.LittleEndian.PutUint32(buf[16:20], 16)
+	binary.LittleEndian.PutUint16(buf[20:22], wavFormatPCM)
+	binary.LittleEndian.PutUint16(buf[22:24], 1)
+	binary.LittleEndian.PutUint32(buf[24:28], uint32(sampleRate))
+	binary.LittleEndian.PutUint32(buf[28:32], uint32(sampleRate*2))
+	binary.LittleEndian.PutUint16(buf[32:34], 2)
+	binary.LittleEndian.PutUint16(buf[34:36], 16)
+	copy(buf[36:40], "data")
+	binary.LittleEndian.PutUint32(buf[40:44], uint32(dataSize))
+	for i, s := range samples {
+		binary.LittleEndian.PutUint16(buf[44+i*2:], uint16(s))
+	}
+	if _, err := w.Write(buf); err != nil {
+		return fmt.Errorf("wav error:%v", err)
+	}
+	return nil
+}
+
+//DecodeWAVFile decodes the WAV file at path as a single utterance, returning up to numNbest results like ProcessUtt. The file must be 16-bit pcm; multiple channels are downmixed, and audio at another rate than the decoder's is resampled.
+func (p *PocketSphinx) DecodeWAVFile(path string, numNbest int) ([]Result, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	format, samples, err := ReadWAV(f)
+	if err != nil {
+		return nil, err
+	}
+	if format.Channels > 1 {
+		samples = Downmix(samples[:0], samples, format.Channels)
+		format.Channels = 1
+	}
+	rate, err := p.decoderRate()
+	if err != nil {
+		return nil, err
+	}
+	if float64(format.SampleRate) != rate && format.SampleRate > 0 {
+		samples = Resample(samples, format.SampleRate, int(rate))
+		format.SampleRate = int(rate)
+	}
+	if err := format.Validate(rate); err != nil {
+		return nil, err
+	}
+	return p.ProcessUtt(samples, numNbest)
+}
@@ -0,0 +1,132 @@
+package pocketsphinx
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+//wavFile builds a WAV file with a fmt chunk of the given format, preceded by a LIST chunk of odd size to check chunks are skipped with their padding.
+func wavFile(format uint16, channels, rate, bits int, data []byte) []byte {
+	var b bytes.Buffer
+	b.WriteString("RIFF")
+	binary.Write(&b, binary.LittleEndian, uint32(0))
+	b.WriteString("WAVE")
+	b.WriteString("LIST")
+	binary.Write(&b, binary.LittleEndian, uint32(3))
+	b.WriteString("abc\x00")
+	b.WriteString("fmt ")
+	binary.Write(&b, binary.LittleEndian, []uint32{16})
+	binary.Write(&b, binary.LittleEndian, []uint16{format, uint16(channels)})
+	binary.Write(&b, binary.LittleEndian, []uint32{uint32(rate), uint32(rate * channels * bits / 8)})
+	binary.Write(&b, binary.LittleEndian, []uint16{uint16(channels * bits / 8), uint16(bits)})
+	b.WriteString("data")
+	binary.Write(&b, binary.LittleEndian, uint32(len(data)))
+	b.Write(data)
+	return b.Bytes()
+}
+
+func TestReadWAV(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    []byte
+		format  WAVFormat
+		samples []int16
+		err     string
+	}{
+		{
+			name:    "mono",
+			file:    wavFile(wavFormatPCM, 1, 16000, 16, []byte{1, 0, 0xff, 0xff, 0, 0x80}),
+			format:  WAVFormat{AudioFormat: wavFormatPCM, Channels: 1, SampleRate: 16000, BitsPerSample: 16},
+			samples: []int16{1, -1, -32768},
+		},
+		{
+			name:    "stereo interleaved",
+			file:    wavFile(wavFormatPCM, 2, 8000, 16, []byte{1, 0, 2, 0, 3, 0, 4, 0}),
+			format:  WAVFormat{AudioFormat: wavFormatPCM, Channels: 2, SampleRate: 8000, BitsPerSample: 16},
+			samples: []int16{1, 2, 3, 4},
+		},
+		{
+			name:   "8-bit",
+			file:   wavFile(wavFormatPCM, 1, 8000, 8, []byte{1, 2}),
+			format: WAVFormat{AudioFormat: wavFormatPCM, Channels: 1, SampleRate: 8000, BitsPerSample: 8},
+			err:    "unsupported audio format",
+		},
+		{
+			name:   "mu-law",
+			file:   wavFile(7, 1, 8000, 8, []byte{1, 2}),
+			format: WAVFormat{AudioFormat: 7, Channels: 1, SampleRate: 8000, BitsPerSample: 8},
+			err:    "unsupported audio format",
+		},
+		{name: "not wav", file: []byte("RIFF\x00\x00\x00\x00AVI LIST"), err: "not a RIFF WAVE file"},
+		{name: "truncated", file: []byte("RIFF"), err: "wav error"},
+		{name: "no data", file: wavFile(wavFormatPCM, 1, 16000, 16, nil)[:48], err: "no data chunk"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, samples, err := ReadWAV(bytes.NewReader(tt.file))
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("ReadWAV error %v, want %q", err, tt.err)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if format != tt.format {
+				t.Errorf("got format %+v, want %+v", format, tt.format)
+			}
+			if !reflect.DeepEqual(samples, tt.samples) {
+				t.Errorf("got samples %v, want %v", samples, tt.samples)
+			}
+		})
+	}
+}
+
+func TestWriteWAV(t *testing.T) {
+	samples := []int16{0, 1, -1, 32767, -32768}
+	var buf bytes.Buffer
+	if err := WriteWAV(&buf, 16000, samples); err != nil {
+		t.Fatal(err)
+	}
+	w, err := NewWAVReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Format.Validate(16000); err != nil {
+		t.Error(err)
+	}
+	if w.Duration() != 5*time.Second/16000 {
+		t.Errorf("got duration %v", w.Duration())
+	}
+	_, got, err := ReadWAV(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, samples) {
+		t.Errorf("got samples %v, want %v", got, samples)
+	}
+}
+
+func TestWAVFormatValidate(t *testing.T) {
+	pcm := WAVFormat{AudioFormat: wavFormatPCM, Channels: 1, SampleRate: 16000, BitsPerSample: 16}
+	tests := []struct {
+		name    string
+		format  WAVFormat
+		rate    float64
+		wantErr bool
+	}{
+		{name: "valid", format: pcm, rate: 16000},
+		{name: "sample rate", format: pcm, rate: 8000, wantErr: true},
+		{name: "stereo", format: WAVFormat{AudioFormat: wavFormatPCM, Channels: 2, SampleRate: 16000, BitsPerSample: 16}, rate: 16000, wantErr: true},
+		{name: "8-bit", format: WAVFormat{AudioFormat: wavFormatPCM, Channels: 1, SampleRate: 16000, BitsPerSample: 8}, rate: 16000, wantErr: true},
+		{name: "float", format: WAVFormat{AudioFormat: 3, Channels: 1, SampleRate: 16000, BitsPerSample: 16}, rate: 16000, wantErr: true},
+	}
+	for _, tt := range tests {
+		if err := tt.format.Validate(tt.rate); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate error %v, want error %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
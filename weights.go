@@ -0,0 +1,107 @@
+package pocketsphinx
+
+/*
+#include <pocketsphinx.h>
+*/
+import "C"
+
+import (
+	"errors"
+)
+
+//LMWeights are the weights balancing the language model against the acoustic model in n-gram search. Zero values keep the current setting.
+type LMWeights struct {
+	//LW is the language weight (-lw), scaling language model scores. Higher values favor likely word sequences over what the audio sounds like.
+	LW float64 `json:"lw,omitempty" yaml:"lw,omitempty"`
+	//WIP is the word insertion penalty (-wip), the probability of adding a word. Lower values produce fewer, longer words.
+	WIP float64 `json:"wip,omitempty" yaml:"wip,omitempty"`
+	//PIP is the phone insertion penalty (-pip).
+	PIP float64 `json:"pip,omitempty" yaml:"pip,omitempty"`
+	//SilProb is the probability of silence between words (-silprob).
+	SilProb float64 `json:"silprob,omitempty" yaml:"silprob,omitempty"`
+}
+
+func (w LMWeights) flags(f func(key string, val float64)) {
+	for _, fl := range []struct {
+		key string
+		val float64
+	}{{"-lw", w.LW}, {"-wip", w.WIP}, {"-pip", w.PIP}, {"-silprob", w.SilProb}} {
+		if fl.val != 0 {
+			f(fl.key, fl.val)
+		}
+	}
+}
+
+//WithLMWeights sets the fields of w that are set.
+func WithLMWeights(w LMWeights) Option {
+	return func(psConfig *C.cmd_ln_t) {
+		w.flags(func(key string, val float64) {
+			setFloatParam(psConfig, key, val)
+		})
+	}
+}
+
+//LMWeights gets the current weights.
+func (p *PocketSphinx) LMWeights() (LMWeights, error) {
+	if err := p.acquire(); err != nil {
+		return LMWeights{}, err
+	}
+	defer p.release()
+	psConfig := C.ps_get_config(p.ps)
+	return LMWeights{
+		LW:      getFloatParam(psConfig, "-lw"),
+		WIP:     getFloatParam(psConfig, "-wip"),
+		PIP:     getFloatParam(psConfig, "-pip"),
+		SilProb: getFloatParam(psConfig, "-silprob"),
+	}, nil
+}
+
+//SetLMWeights changes the fields of w that are set and reinitializes the decoder to apply them, which drops the searches added at runtime. To try another language weight on the last utterance, use Rescore.
+func (p *PocketSphinx) SetLMWeights(w LMWeights) error {
+	var err error
+	w.flags(func(key string, val float64) {
+		if err == nil {
+			err = p.SetOption(key, val)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	return p.Reinit()
+}
+
+//Rescore searches the lattice of the last utterance again for the best hypothesis, weighting the language model by lw instead of -lw, without decoding the audio again. It is only available after n-gram search. Prob and Confidence of the result are not set; the words have the posterior probabilities of the lattice.
+func (p *PocketSphinx) Rescore(lw float64) (Result, error) {
+	if lw <= 0 {
+		return Result{}, errors.New("rescore error:language weight must be positive")
+	}
+	if err := p.acquire(); err != nil {
+		return Result{}, err
+	}
+	defer p.release()
+	psConfig := C.ps_get_config(p.ps)
+	lwf := lw / getFloatParam(psConfig, "-lw")
+	ascale := getFloatParam(psConfig, "-ascale")
+
+	dag := C.ps_get_lattice(p.ps)
+	if dag == nil {
+		return Result{}, errors.New("rescore error:no lattice")
+	}
+	lm := C.ps_get_lm(p.ps, C.ps_get_search(p.ps))
+	if lm == nil {
+		return Result{}, errors.New("rescore error:not an n-gram search")
+	}
+	link := C.ps_lattice_bestpath(dag, lm, C.float32(lwf), C.float32(ascale))
+	if link == nil {
+		return Result{}, ErrNoHypothesis
+	}
+	C.ps_lattice_posterior(dag, lm, C.float32(ascale))
+
+	r := Result{Text: C.GoString(C.ps_lattice_hyp(dag, link))}
+	r.Segments = collectSegments(C.ps_lattice_seg_iter(dag, link, C.float32(lwf)))
+	for _, seg := range r.Segments {
+		r.Score += seg.Ascr + seg.Lscr
+	}
+	r.Words = p.appendWordResults(nil, r.Segments)
+	return r, nil
+}